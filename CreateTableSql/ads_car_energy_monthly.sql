@@ -0,0 +1,16 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for ads_car_energy_monthly
+-- ----------------------------
+DROP TABLE IF EXISTS `ads_car_energy_monthly`;
+CREATE TABLE `ads_car_energy_monthly`  (
+  `period` char(7) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '年月（YYYY-MM）',
+  `energy_type` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '能源类型',
+  `car_count` bigint NOT NULL DEFAULT 0 COMMENT '汽车数量',
+  PRIMARY KEY (`period`, `energy_type`) USING BTREE,
+  INDEX `idx_energy_type`(`energy_type` ASC) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '能源类型月度分布表' ROW_FORMAT = Dynamic;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,15 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for ads_car_level_energy_distribution
+-- ----------------------------
+DROP TABLE IF EXISTS `ads_car_level_energy_distribution`;
+CREATE TABLE `ads_car_level_energy_distribution`  (
+  `car_level` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '汽车级别',
+  `energy_type` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '能源类型',
+  `car_count` bigint NOT NULL DEFAULT 0 COMMENT '汽车数量',
+  PRIMARY KEY (`car_level`, `energy_type`) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '汽车级别能源类型分布表' ROW_FORMAT = Dynamic;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,16 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for ads_car_level_monthly
+-- ----------------------------
+DROP TABLE IF EXISTS `ads_car_level_monthly`;
+CREATE TABLE `ads_car_level_monthly`  (
+  `period` char(7) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '年月（YYYY-MM）',
+  `car_level` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '汽车级别',
+  `car_count` bigint NOT NULL DEFAULT 0 COMMENT '汽车数量',
+  PRIMARY KEY (`period`, `car_level`) USING BTREE,
+  INDEX `idx_car_level`(`car_level` ASC) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '汽车级别月度分布表' ROW_FORMAT = Dynamic;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,16 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for brand_sales_monthly
+-- ----------------------------
+DROP TABLE IF EXISTS `brand_sales_monthly`;
+CREATE TABLE `brand_sales_monthly`  (
+  `period` char(7) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '年月（YYYY-MM）',
+  `brand_name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '品牌名称',
+  `total_sales` decimal(20, 2) NOT NULL DEFAULT 0 COMMENT '总销售额',
+  PRIMARY KEY (`period`, `brand_name`) USING BTREE,
+  INDEX `idx_brand_name`(`brand_name` ASC) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '品牌月度销售表' ROW_FORMAT = Dynamic;
+
+SET FOREIGN_KEY_CHECKS = 1;
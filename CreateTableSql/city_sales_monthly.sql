@@ -0,0 +1,16 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for city_sales_monthly
+-- ----------------------------
+DROP TABLE IF EXISTS `city_sales_monthly`;
+CREATE TABLE `city_sales_monthly`  (
+  `period` char(7) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '年月（YYYY-MM）',
+  `city` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '城市名称',
+  `sales` decimal(20, 2) NOT NULL DEFAULT 0 COMMENT '销售数量',
+  PRIMARY KEY (`period`, `city`) USING BTREE,
+  INDEX `idx_city`(`city` ASC) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '城市月度销售表' ROW_FORMAT = Dynamic;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,174 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for dim_city_region
+-- 城市与省份、大区的对应关系，下钻分析（大区→省份→城市）使用
+-- 城市名称需与 dwd_car_sales_detail.city 一致，未收录的城市在下钻时归入“未知”
+-- 新增或调整城市时修改本文件的数据并重新执行即可
+-- ----------------------------
+DROP TABLE IF EXISTS `dim_city_region`;
+CREATE TABLE `dim_city_region`  (
+  `city` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '城市名称',
+  `province` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '省份',
+  `region` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '大区',
+  PRIMARY KEY (`city`) USING BTREE,
+  INDEX `idx_region_province`(`region` ASC, `province` ASC) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '城市大区参考表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of dim_city_region
+-- ----------------------------
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('北京市', '北京市', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('天津市', '天津市', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('石家庄市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('唐山市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('保定市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('邯郸市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('廊坊市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('沧州市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('邢台市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('秦皇岛市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('张家口市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('承德市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('衡水市', '河北省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('太原市', '山西省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('大同市', '山西省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('长治市', '山西省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('晋中市', '山西省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('运城市', '山西省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('临汾市', '山西省', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('呼和浩特市', '内蒙古自治区', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('包头市', '内蒙古自治区', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('鄂尔多斯市', '内蒙古自治区', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('赤峰市', '内蒙古自治区', '华北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('沈阳市', '辽宁省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('大连市', '辽宁省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('鞍山市', '辽宁省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('锦州市', '辽宁省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('营口市', '辽宁省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('长春市', '吉林省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('吉林市', '吉林省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('哈尔滨市', '黑龙江省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('大庆市', '黑龙江省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('齐齐哈尔市', '黑龙江省', '东北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('上海市', '上海市', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('南京市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('苏州市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('无锡市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('常州市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('南通市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('徐州市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('扬州市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('盐城市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('泰州市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('镇江市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('淮安市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('连云港市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('宿迁市', '江苏省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('杭州市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('宁波市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('温州市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('嘉兴市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('绍兴市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('金华市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('台州市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('湖州市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('衢州市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('丽水市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('舟山市', '浙江省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('合肥市', '安徽省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('芜湖市', '安徽省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('蚌埠市', '安徽省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('阜阳市', '安徽省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('安庆市', '安徽省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('马鞍山市', '安徽省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('滁州市', '安徽省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('福州市', '福建省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('厦门市', '福建省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('泉州市', '福建省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('漳州市', '福建省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('莆田市', '福建省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('龙岩市', '福建省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('南昌市', '江西省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('赣州市', '江西省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('九江市', '江西省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('上饶市', '江西省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('宜春市', '江西省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('济南市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('青岛市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('烟台市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('潍坊市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('临沂市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('济宁市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('淄博市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('威海市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('菏泽市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('德州市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('聊城市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('泰安市', '山东省', '华东');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('郑州市', '河南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('洛阳市', '河南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('南阳市', '河南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('新乡市', '河南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('商丘市', '河南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('周口市', '河南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('许昌市', '河南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('信阳市', '河南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('安阳市', '河南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('武汉市', '湖北省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('宜昌市', '湖北省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('襄阳市', '湖北省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('荆州市', '湖北省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('黄冈市', '湖北省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('孝感市', '湖北省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('长沙市', '湖南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('株洲市', '湖南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('湘潭市', '湖南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('衡阳市', '湖南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('岳阳市', '湖南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('常德市', '湖南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('郴州市', '湖南省', '华中');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('广州市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('深圳市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('东莞市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('佛山市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('惠州市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('中山市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('珠海市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('江门市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('汕头市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('湛江市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('肇庆市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('揭阳市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('茂名市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('清远市', '广东省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('南宁市', '广西壮族自治区', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('柳州市', '广西壮族自治区', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('桂林市', '广西壮族自治区', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('玉林市', '广西壮族自治区', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('海口市', '海南省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('三亚市', '海南省', '华南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('重庆市', '重庆市', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('成都市', '四川省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('绵阳市', '四川省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('德阳市', '四川省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('宜宾市', '四川省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('南充市', '四川省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('泸州市', '四川省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('贵阳市', '贵州省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('遵义市', '贵州省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('昆明市', '云南省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('曲靖市', '云南省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('大理白族自治州', '云南省', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('拉萨市', '西藏自治区', '西南');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('西安市', '陕西省', '西北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('咸阳市', '陕西省', '西北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('宝鸡市', '陕西省', '西北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('榆林市', '陕西省', '西北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('兰州市', '甘肃省', '西北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('西宁市', '青海省', '西北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('银川市', '宁夏回族自治区', '西北');
+INSERT INTO `dim_city_region` (`city`, `province`, `region`) VALUES ('乌鲁木齐市', '新疆维吾尔自治区', '西北');
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,24 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for dwd_car_sales_detail
+-- ----------------------------
+DROP TABLE IF EXISTS `dwd_car_sales_detail`;
+CREATE TABLE `dwd_car_sales_detail`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `period` char(7) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '年月（YYYY-MM）',
+  `brand_name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '品牌名称',
+  `series` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '车系',
+  `model` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '车型',
+  `city` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '城市名称',
+  `car_level` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '汽车级别',
+  `energy_type` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '能源类型',
+  `sales` bigint NOT NULL DEFAULT 0 COMMENT '销量',
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_period`(`period` ASC) USING BTREE,
+  INDEX `idx_brand_series_model`(`brand_name` ASC, `series` ASC, `model` ASC) USING BTREE,
+  INDEX `idx_city`(`city` ASC) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '汽车销量明细表' ROW_FORMAT = Dynamic;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,26 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for permissions
+-- ----------------------------
+DROP TABLE IF EXISTS `permissions`;
+CREATE TABLE `permissions`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '权限名（资源:操作）',
+  `description` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL DEFAULT NULL COMMENT '描述',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_name`(`name` ASC) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '权限表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of permissions
+-- ----------------------------
+INSERT INTO `permissions` (`id`, `name`, `description`) VALUES (1, 'users:read', '查看用户');
+INSERT INTO `permissions` (`id`, `name`, `description`) VALUES (2, 'users:write', '管理用户');
+INSERT INTO `permissions` (`id`, `name`, `description`) VALUES (3, 'datasets:import', '导入数据集');
+INSERT INTO `permissions` (`id`, `name`, `description`) VALUES (4, 'analytics:read', '查看分析数据');
+INSERT INTO `permissions` (`id`, `name`, `description`) VALUES (5, 'audit:read', '查看审计日志');
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,29 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for role_permissions
+-- ----------------------------
+DROP TABLE IF EXISTS `role_permissions`;
+CREATE TABLE `role_permissions`  (
+  `role_id` bigint UNSIGNED NOT NULL,
+  `permission_id` bigint UNSIGNED NOT NULL,
+  PRIMARY KEY (`role_id`, `permission_id`) USING BTREE,
+  INDEX `idx_permission_id`(`permission_id` ASC) USING BTREE,
+  CONSTRAINT `role_permissions_ibfk_1` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT,
+  CONSTRAINT `role_permissions_ibfk_2` FOREIGN KEY (`permission_id`) REFERENCES `permissions` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '角色权限关联表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of role_permissions
+-- ----------------------------
+-- system: 全部权限
+INSERT INTO `role_permissions` (`role_id`, `permission_id`) VALUES (1, 1);
+INSERT INTO `role_permissions` (`role_id`, `permission_id`) VALUES (1, 2);
+INSERT INTO `role_permissions` (`role_id`, `permission_id`) VALUES (1, 3);
+INSERT INTO `role_permissions` (`role_id`, `permission_id`) VALUES (1, 4);
+INSERT INTO `role_permissions` (`role_id`, `permission_id`) VALUES (1, 5);
+-- app: 只读分析数据
+INSERT INTO `role_permissions` (`role_id`, `permission_id`) VALUES (2, 4);
+
+SET FOREIGN_KEY_CHECKS = 1;
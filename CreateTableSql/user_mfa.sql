@@ -0,0 +1,50 @@
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ----------------------------
+-- Table structure for user_mfa
+-- ----------------------------
+DROP TABLE IF EXISTS `user_mfa`;
+CREATE TABLE `user_mfa`  (
+  `user_id` bigint UNSIGNED NOT NULL,
+  `secret` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'TOTP密钥（Base32）',
+  `enabled` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否已启用',
+  `last_used_step` bigint NOT NULL DEFAULT 0 COMMENT '最近使用的时间步（防重放）',
+  `confirmed_at` timestamp NULL DEFAULT NULL COMMENT '绑定确认时间',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`) USING BTREE,
+  CONSTRAINT `user_mfa_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '用户二次验证表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for user_mfa_recovery_codes
+-- ----------------------------
+DROP TABLE IF EXISTS `user_mfa_recovery_codes`;
+CREATE TABLE `user_mfa_recovery_codes`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `user_id` bigint UNSIGNED NOT NULL,
+  `code_hash` char(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '恢复码SHA-256哈希',
+  `used_at` timestamp NULL DEFAULT NULL COMMENT '使用时间',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_user_id`(`user_id` ASC) USING BTREE,
+  CONSTRAINT `user_mfa_recovery_codes_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '二次验证恢复码表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for user_mfa_challenge_uses
+-- ----------------------------
+DROP TABLE IF EXISTS `user_mfa_challenge_uses`;
+CREATE TABLE `user_mfa_challenge_uses`  (
+  `jti` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '挑战令牌ID',
+  `user_id` bigint UNSIGNED NOT NULL,
+  `expires_at` timestamp NOT NULL COMMENT '挑战令牌过期时间，过期后记录可清理',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`jti`) USING BTREE,
+  INDEX `idx_user_id`(`user_id` ASC) USING BTREE,
+  INDEX `idx_expires_at`(`expires_at` ASC) USING BTREE,
+  CONSTRAINT `user_mfa_challenge_uses_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '已使用的二次验证挑战令牌表' ROW_FORMAT = Dynamic;
+
+SET FOREIGN_KEY_CHECKS = 1;
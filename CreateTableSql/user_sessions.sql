@@ -24,17 +24,23 @@ DROP TABLE IF EXISTS `user_sessions`;
 CREATE TABLE `user_sessions`  (
   `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
   `user_id` bigint UNSIGNED NOT NULL,
-  `token` varchar(512) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'JWT令牌',
-  `refresh_token` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '刷新令牌',
+  `family_id` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '会话族ID（同一次登录的轮换链共享）',
+  `token` varchar(512) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'JWT令牌哈希',
+  `refresh_token` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '刷新令牌哈希',
   `expires_at` timestamp NOT NULL COMMENT '令牌过期时间',
   `refresh_expires_at` timestamp NOT NULL COMMENT '刷新令牌过期时间',
   `user_agent` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL COMMENT '用户客户端信息',
   `ip_address` varchar(45) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL DEFAULT NULL COMMENT '用户IP地址',
+  `revoked_at` timestamp NULL DEFAULT NULL COMMENT '吊销时间',
+  `revoke_reason` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL DEFAULT NULL COMMENT '吊销原因',
+  `replaced_by_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '轮换后的新会话ID',
+  `last_seen_at` timestamp NULL DEFAULT NULL COMMENT '最近活跃时间',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`) USING BTREE,
   INDEX `idx_user_id`(`user_id` ASC) USING BTREE,
+  INDEX `idx_family_id`(`family_id` ASC) USING BTREE,
   INDEX `idx_token`(`token`(100) ASC) USING BTREE,
-  INDEX `idx_refresh_token`(`refresh_token` ASC) USING BTREE,
+  UNIQUE INDEX `idx_refresh_token`(`refresh_token` ASC) USING BTREE,
   INDEX `idx_expires`(`expires_at` ASC) USING BTREE,
   CONSTRAINT `user_sessions_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
 ) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '用户会话表' ROW_FORMAT = Dynamic;
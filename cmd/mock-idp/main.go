@@ -0,0 +1,45 @@
+// mock-idp 本地开发和联调用的 OIDC 身份提供方模拟服务
+//
+// 支持授权码 + PKCE（S256）流程：/authorize 不显示登录页，直接以 -email/-sub 指定的用户
+// （或请求中的 login_hint 邮箱）完成授权并回跳 redirect_uri。
+//
+//	go run ./cmd/mock-idp -addr 127.0.0.1:9090 -client-id dashboard -client-secret dev-secret
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"go-web/pkg/oidc/mockidp"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:9090", "监听地址")
+	issuer := flag.String("issuer", "", "发行方地址，默认 http://<addr>")
+	clientID := flag.String("client-id", "dashboard", "允许的 client_id")
+	clientSecret := flag.String("client-secret", "dev-secret", "客户端密钥，为空表示公共客户端")
+	subject := flag.String("sub", "mock-user-1", "默认用户的 sub")
+	email := flag.String("email", "sso.user@example.com", "默认用户的邮箱")
+	name := flag.String("name", "SSO User", "默认用户的姓名")
+	flag.Parse()
+
+	if *issuer == "" {
+		*issuer = "http://" + *addr
+	}
+
+	idp, err := mockidp.New(mockidp.Config{
+		Issuer:       *issuer,
+		ClientID:     *clientID,
+		ClientSecret: *clientSecret,
+		Subject:      *subject,
+		Email:        *email,
+		Name:         *name,
+	})
+	if err != nil {
+		log.Fatalf("生成签名密钥失败: %v", err)
+	}
+
+	log.Printf("mock IdP 已启动: issuer=%s client_id=%s", idp.Issuer(), *clientID)
+	log.Fatal(http.ListenAndServe(*addr, idp))
+}
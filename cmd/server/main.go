@@ -2,8 +2,12 @@ package main
 
 import (
 	"fmt"
+	"time"
 	"go-web/pkg/logger"
+	"go-web/pkg/mailer"
 	"go-web/pkg/setting"
+	"go-web/pkg/token"
+	"go-web/internal/auth"
 	"go-web/internal/dao"
 	"go-web/internal/routers"
 	"github.com/spf13/viper"
@@ -26,6 +30,29 @@ func main() {
 
 	logger.Info("开始初始化应用...")
 
+	// 加载 JWT 签名密钥，未配置密钥时拒绝启动
+	if err := token.InitKeyring(); err != nil {
+		logger.Fatalf("JWT 密钥加载失败: %v", err)
+	}
+
+	// 初始化第三方登录（OIDC）身份提供方
+	if err := auth.InitOAuthProviders(); err != nil {
+		logger.Fatalf("第三方登录配置错误: %v", err)
+	}
+
+	// 加载密码策略和泄露密码列表
+	if err := auth.InitPasswordPolicy(); err != nil {
+		logger.Fatalf("密码策略配置错误: %v", err)
+	}
+
+	// 初始化登录失败限制（默认使用内存存储）
+	auth.InitLoginLimiters(auth.NewMemoryAttemptStore())
+
+	// 初始化邮件发送器
+	if err := mailer.InitMailer(); err != nil {
+		logger.Fatalf("邮件发送器初始化失败: %v", err)
+	}
+
 	// 初始化数据库
 	if err := dao.InitDB(); err != nil {
 		logger.Fatalf("数据库初始化失败: %v", err)
@@ -42,6 +69,19 @@ func main() {
 
 	logger.Info("数据库初始化成功")
 
+	// 加载会话吊销列表，并定期与数据库同步
+	if err := auth.Revocations.Sync(); err != nil {
+		logger.Fatalf("会话吊销列表加载失败: %v", err)
+	}
+	stopRevocationSync := auth.Revocations.StartSync(30 * time.Second)
+	defer stopRevocationSync()
+
+	// 开启邮箱验证时，定期清理超时未验证的账户
+	if auth.EmailVerificationEnabled() {
+		stopUnverifiedCleanup := auth.StartUnverifiedCleanup(time.Hour)
+		defer stopUnverifiedCleanup()
+	}
+
 	// 设置路由
 	engine := routers.SetupRouter()
 	logger.Info("路由设置完成")
@@ -0,0 +1,121 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/stats"
+
+	"github.com/spf13/viper"
+)
+
+// defaultNEVKeywords 新能源能源类型的默认关键字：纯电、插电混动、增程、燃料电池
+// 普通油电混合（HEV）和轻混不计入新能源
+var defaultNEVKeywords = []string{
+	"纯电", "插电", "插混", "增程", "燃料电池", "氢",
+	"bev", "phev", "erev", "reev", "fcev", "ev",
+}
+
+// nevKeywords 判断新能源使用的关键字（analytics.nev_keywords），未配置时使用默认值
+func nevKeywords() []string {
+	keywords := viper.GetStringSlice("analytics.nev_keywords")
+	if len(keywords) == 0 {
+		return defaultNEVKeywords
+	}
+	return keywords
+}
+
+// IsNEV 判断能源类型是否属于新能源，按关键字匹配（不区分大小写）
+// 英文缩写按完整单词匹配，避免 "HEV" 被 "ev" 误判
+func IsNEV(energyType string) bool {
+	name := strings.ToLower(strings.TrimSpace(energyType))
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+
+	for _, keyword := range nevKeywords() {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword == "" {
+			continue
+		}
+		if isASCIIWord(keyword) {
+			for _, word := range words {
+				if word == keyword {
+					return true
+				}
+			}
+			continue
+		}
+		if strings.Contains(name, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isASCIIWord 判断关键字是否只包含英文字母和数字
+func isASCIIWord(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// AnalyzeEnergy 根据能源类型分布计算新能源渗透率和能源构成，values 按数量降序排列
+func AnalyzeEnergy(values []dao.AnalyticsValue) models.EnergyAnalysis {
+	analysis := models.EnergyAnalysis{Mix: make([]models.EnergyMixItem, 0, len(values))}
+	for _, v := range values {
+		analysis.Total += v.Value
+		if IsNEV(v.Name) {
+			analysis.NEV += v.Value
+		} else {
+			analysis.ICE += v.Value
+		}
+	}
+	analysis.Penetration = stats.Round(stats.Percent(analysis.NEV, analysis.Total), 4)
+
+	for _, v := range values {
+		analysis.Mix = append(analysis.Mix, models.EnergyMixItem{
+			EnergyName: v.Name,
+			Count:      v.Value,
+			Share:      stats.Round(stats.Percent(v.Value, analysis.Total), 4),
+			NEV:        IsNEV(v.Name),
+		})
+	}
+	return analysis
+}
+
+// Penetration 按维度汇总新能源渗透率，结果按总量降序排列
+func Penetration(rows []dao.EnergyBreakdownRow) []models.PenetrationItem {
+	index := make(map[string]int)
+	items := make([]models.PenetrationItem, 0)
+	for _, row := range rows {
+		i, ok := index[row.Name]
+		if !ok {
+			i = len(items)
+			index[row.Name] = i
+			items = append(items, models.PenetrationItem{Name: row.Name})
+		}
+		items[i].Total += row.Value
+		if IsNEV(row.EnergyType) {
+			items[i].NEV += row.Value
+		} else {
+			items[i].ICE += row.Value
+		}
+	}
+
+	for i := range items {
+		items[i].Penetration = stats.Round(stats.Percent(items[i].NEV, items[i].Total), 4)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Total != items[j].Total {
+			return items[i].Total > items[j].Total
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
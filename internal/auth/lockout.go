@@ -0,0 +1,328 @@
+package auth
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// LoginAttempt 某个键（账户或IP）的失败记录
+type LoginAttempt struct {
+	Failures    int       // 本轮锁定前的失败次数
+	Lockouts    int       // 已触发的锁定次数，用于计算指数退避
+	LockedUntil time.Time // 锁定截止时间
+	LastFailure time.Time // 最近一次失败时间
+}
+
+// AttemptStore 失败记录存储，可替换为 Redis 等共享存储以支持多实例部署
+type AttemptStore interface {
+	Get(key string) (LoginAttempt, bool)
+	Set(key string, attempt LoginAttempt)
+	Delete(key string)
+	// Update 原子地读取、修改并保存记录，fn 执行期间同一键的其他更新必须等待
+	// 共享存储可通过 Lua 脚本或 WATCH/MULTI 实现
+	Update(key string, fn func(attempt LoginAttempt, ok bool) LoginAttempt)
+}
+
+// MemoryAttemptStore 基于内存的失败记录存储（默认实现）
+type MemoryAttemptStore struct {
+	mu          sync.Mutex
+	items       map[string]LoginAttempt
+	expire      time.Duration // 超过该时长未更新的记录会被清理
+	lastCleanup time.Time
+}
+
+// NewMemoryAttemptStore 创建内存存储
+func NewMemoryAttemptStore() *MemoryAttemptStore {
+	return &MemoryAttemptStore{
+		items:  make(map[string]LoginAttempt),
+		expire: 24 * time.Hour,
+	}
+}
+
+// Get 获取失败记录
+func (s *MemoryAttemptStore) Get(key string) (LoginAttempt, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.items[key]
+	return attempt, ok
+}
+
+// Set 保存失败记录，并定期清理过期记录
+func (s *MemoryAttemptStore) Set(key string, attempt LoginAttempt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, attempt)
+}
+
+// Update 在持有锁的情况下读取、修改并保存记录
+func (s *MemoryAttemptStore) Update(key string, fn func(attempt LoginAttempt, ok bool) LoginAttempt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.items[key]
+	s.set(key, fn(attempt, ok))
+}
+
+// set 保存记录并定期清理过期记录，调用方需持有锁
+func (s *MemoryAttemptStore) set(key string, attempt LoginAttempt) {
+	s.items[key] = attempt
+
+	now := time.Now()
+	if now.Sub(s.lastCleanup) < time.Minute {
+		return
+	}
+	s.lastCleanup = now
+	for k, v := range s.items {
+		if now.Sub(v.LastFailure) > s.expire && now.After(v.LockedUntil) {
+			delete(s.items, k)
+		}
+	}
+}
+
+// Delete 删除失败记录
+func (s *MemoryAttemptStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.items, key)
+}
+
+// LockoutPolicy 锁定策略
+type LockoutPolicy struct {
+	MaxFailures int           // 触发锁定的失败次数
+	BaseLockout time.Duration // 首次锁定时长，之后每次锁定翻倍
+	MaxLockout  time.Duration // 锁定时长上限
+	ResetAfter  time.Duration // 距最近一次失败超过该时长后清零全部记录
+}
+
+// Limiter 按键统计失败次数并执行指数退避锁定
+type Limiter struct {
+	store  AttemptStore
+	policy LockoutPolicy
+	now    func() time.Time
+}
+
+// NewLimiter 创建限制器
+func NewLimiter(store AttemptStore, policy LockoutPolicy) *Limiter {
+	return &Limiter{store: store, policy: policy, now: time.Now}
+}
+
+// Check 检查键是否处于锁定状态，锁定时返回剩余等待时间
+func (l *Limiter) Check(key string) (time.Duration, bool) {
+	attempt, ok := l.load(key)
+	if !ok {
+		return 0, false
+	}
+
+	if wait := attempt.LockedUntil.Sub(l.now()); wait > 0 {
+		return wait, true
+	}
+	return 0, false
+}
+
+// Fail 记录一次失败，达到阈值时触发锁定；处于锁定状态时返回剩余锁定时长
+// 读取和累加在存储内原子完成，并发的失败请求不会越过阈值
+func (l *Limiter) Fail(key string) (time.Duration, bool) {
+	now := l.now()
+
+	var lockout time.Duration
+	l.store.Update(key, func(attempt LoginAttempt, ok bool) LoginAttempt {
+		if ok && l.expired(attempt, now) {
+			attempt = LoginAttempt{}
+		}
+
+		// 锁定期间的失败不再累加，避免锁定时长被反复延长
+		if wait := attempt.LockedUntil.Sub(now); wait > 0 {
+			lockout = wait
+			return attempt
+		}
+
+		// 上一轮锁定已结束，重新开始计数（保留锁定次数以便继续翻倍）
+		if !attempt.LockedUntil.IsZero() && now.After(attempt.LockedUntil) {
+			attempt.Failures = 0
+			attempt.LockedUntil = time.Time{}
+		}
+
+		attempt.Failures++
+		attempt.LastFailure = now
+
+		if attempt.Failures >= l.policy.MaxFailures {
+			lockout = l.lockoutDuration(attempt.Lockouts)
+			attempt.Lockouts++
+			attempt.LockedUntil = now.Add(lockout)
+		}
+		return attempt
+	})
+
+	return lockout, lockout > 0
+}
+
+// Reset 清除键的失败记录（如登录成功）
+func (l *Limiter) Reset(key string) {
+	l.store.Delete(key)
+}
+
+// LockedUntil 返回键的锁定截止时间，未锁定时返回 false
+func (l *Limiter) LockedUntil(key string) (time.Time, bool) {
+	attempt, ok := l.load(key)
+	if !ok || !attempt.LockedUntil.After(l.now()) {
+		return time.Time{}, false
+	}
+	return attempt.LockedUntil, true
+}
+
+// load 读取记录，距最近一次失败超过 ResetAfter 且未锁定的记录视为不存在
+func (l *Limiter) load(key string) (LoginAttempt, bool) {
+	attempt, ok := l.store.Get(key)
+	if !ok {
+		return LoginAttempt{}, false
+	}
+
+	if l.expired(attempt, l.now()) {
+		l.store.Delete(key)
+		return LoginAttempt{}, false
+	}
+	return attempt, true
+}
+
+// expired 判断记录是否已过期：未锁定且距最近一次失败超过 ResetAfter
+func (l *Limiter) expired(attempt LoginAttempt, now time.Time) bool {
+	return now.After(attempt.LockedUntil) && now.Sub(attempt.LastFailure) > l.policy.ResetAfter
+}
+
+// lockoutDuration 第 n 次（从0开始）锁定的时长：BaseLockout * 2^n，不超过 MaxLockout
+func (l *Limiter) lockoutDuration(n int) time.Duration {
+	d := l.policy.BaseLockout
+	for i := 0; i < n && d < l.policy.MaxLockout; i++ {
+		d *= 2
+	}
+	if d > l.policy.MaxLockout {
+		d = l.policy.MaxLockout
+	}
+	return d
+}
+
+var (
+	// AccountLimiter 按账户统计登录失败
+	AccountLimiter = NewLimiter(NewMemoryAttemptStore(), LockoutPolicy{
+		MaxFailures: 5,
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+		ResetAfter:  15 * time.Minute,
+	})
+
+	// IPLimiter 按客户端IP统计登录失败
+	IPLimiter = NewLimiter(NewMemoryAttemptStore(), LockoutPolicy{
+		MaxFailures: 20,
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+		ResetAfter:  15 * time.Minute,
+	})
+
+	// LookupLimiter 限制用户名/邮箱可用性检查的频率，防止枚举账户
+	LookupLimiter = NewLimiter(NewMemoryAttemptStore(), LockoutPolicy{
+		MaxFailures: 30,
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+		ResetAfter:  10 * time.Minute,
+	})
+
+	// VerificationLimiter 限制重新发送验证邮件的频率，按邮箱和IP分别计数
+	VerificationLimiter = NewLimiter(NewMemoryAttemptStore(), LockoutPolicy{
+		MaxFailures: 3,
+		BaseLockout: time.Hour,
+		MaxLockout:  24 * time.Hour,
+		ResetAfter:  time.Hour,
+	})
+)
+
+// InitLoginLimiters 根据 security.login_lockout 配置初始化登录限制器
+// store 为空时使用内存存储
+func InitLoginLimiters(store AttemptStore) {
+	if store == nil {
+		store = NewMemoryAttemptStore()
+	}
+
+	base := durationOr("security.login_lockout.base_lockout", time.Minute)
+	maxLockout := durationOr("security.login_lockout.max_lockout", time.Hour)
+	resetAfter := durationOr("security.login_lockout.reset_after", 15*time.Minute)
+
+	AccountLimiter = NewLimiter(store, LockoutPolicy{
+		MaxFailures: intOr("security.login_lockout.max_failures", 5),
+		BaseLockout: base,
+		MaxLockout:  maxLockout,
+		ResetAfter:  resetAfter,
+	})
+	IPLimiter = NewLimiter(store, LockoutPolicy{
+		MaxFailures: intOr("security.login_lockout.ip_max_failures", 20),
+		BaseLockout: base,
+		MaxLockout:  maxLockout,
+		ResetAfter:  resetAfter,
+	})
+	LookupLimiter = NewLimiter(store, LockoutPolicy{
+		MaxFailures: intOr("security.login_lockout.lookup_max_requests", 30),
+		BaseLockout: base,
+		MaxLockout:  maxLockout,
+		ResetAfter:  durationOr("security.login_lockout.lookup_window", 10*time.Minute),
+	})
+
+	resendWindow := durationOr("email_verification.resend_window", time.Hour)
+	VerificationLimiter = NewLimiter(store, LockoutPolicy{
+		MaxFailures: intOr("email_verification.resend_max_requests", 3),
+		BaseLockout: resendWindow,
+		MaxLockout:  24 * time.Hour,
+		ResetAfter:  resendWindow,
+	})
+}
+
+// AccountKey 按登录标识（用户名或邮箱）计数的键，各键前缀不同，可共享同一存储
+func AccountKey(identifier string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(identifier))
+}
+
+// UserKey 按用户ID计数的键
+func UserKey(userID uint) string {
+	return "user:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+// IPKey 按客户端IP计数登录失败的键
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// LookupKey 按客户端IP计数可用性检查的键
+func LookupKey(ip string) string {
+	return "lookup:" + ip
+}
+
+// VerificationEmailKey 按邮箱计数重新发送验证邮件的键
+func VerificationEmailKey(email string) string {
+	return "verify-email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// VerificationIPKey 按客户端IP计数重新发送验证邮件的键
+func VerificationIPKey(ip string) string {
+	return "verify-ip:" + ip
+}
+
+// durationOr 读取时长配置，未配置时使用默认值
+func durationOr(key string, fallback time.Duration) time.Duration {
+	if d := viper.GetDuration(key); d > 0 {
+		return d
+	}
+	return fallback
+}
+
+// intOr 读取整数配置，未配置时使用默认值
+func intOr(key string, fallback int) int {
+	if n := viper.GetInt(key); n > 0 {
+		return n
+	}
+	return fallback
+}
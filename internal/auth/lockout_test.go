@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiterLockoutBackoff(t *testing.T) {
+	// 内存存储按系统时间清理过期记录，假时钟从当前时间开始
+	clock := NewFakeClock(time.Now())
+	l := NewLimiter(NewMemoryAttemptStore(), LockoutPolicy{
+		MaxFailures: 3,
+		BaseLockout: time.Minute,
+		MaxLockout:  3 * time.Minute,
+		ResetAfter:  15 * time.Minute,
+	})
+	l.now = clock.Now
+
+	for round, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		for i := 1; i < 3; i++ {
+			if _, locked := l.Fail("k"); locked {
+				t.Fatalf("第 %d 轮第 %d 次失败不应锁定", round+1, i)
+			}
+		}
+		lockout, locked := l.Fail("k")
+		if !locked || lockout != want {
+			t.Fatalf("第 %d 轮锁定时长 = %v, 期望 %v", round+1, lockout, want)
+		}
+		if wait, locked := l.Check("k"); !locked || wait != want {
+			t.Fatalf("第 %d 轮 Check = %v %v", round+1, wait, locked)
+		}
+		// 锁定期间的失败不延长锁定
+		clock.Advance(30 * time.Second)
+		if wait, _ := l.Fail("k"); wait != want-30*time.Second {
+			t.Fatalf("锁定期间失败返回 %v", wait)
+		}
+		clock.Advance(want)
+	}
+
+	clock.Advance(16 * time.Minute)
+	if _, ok := l.load("k"); ok {
+		t.Fatal("超过 ResetAfter 的记录应被清除")
+	}
+	l.Fail("k")
+	l.Reset("k")
+	if _, locked := l.Check("k"); locked {
+		t.Fatal("Reset 后不应处于锁定状态")
+	}
+}
+
+func TestLimiterConcurrentFailures(t *testing.T) {
+	const maxFailures = 5
+	l := NewLimiter(NewMemoryAttemptStore(), LockoutPolicy{
+		MaxFailures: maxFailures,
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+		ResetAfter:  15 * time.Minute,
+	})
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, locked := l.Fail("k"); !locked {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 只有达到阈值前的失败不被锁定，其余请求都应看到锁定
+	if got := allowed.Load(); got != maxFailures-1 {
+		t.Fatalf("未锁定的失败次数 = %d, 期望 %d", got, maxFailures-1)
+	}
+	attempt, _ := l.store.Get("k")
+	if attempt.Failures != maxFailures || attempt.Lockouts != 1 {
+		t.Fatalf("记录 = %+v", attempt)
+	}
+}
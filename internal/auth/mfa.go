@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/token"
+	"go-web/pkg/totp"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+const (
+	mfaSkew           = 1  // 允许前后各一个时间步（30秒）的时钟偏差
+	recoveryCodeCount = 10 // 每次启用生成的恢复码数量
+)
+
+var (
+	ErrMFANotEnrolled      = errors.New("未绑定二次验证")
+	ErrMFAInvalidCode      = errors.New("验证码错误")
+	ErrMFAChallengeInvalid = errors.New("挑战令牌无效")
+	ErrMFAChallengeUsed    = errors.New("挑战令牌已使用")
+)
+
+// MFAClock 二次验证使用的时钟，测试时可替换为 FakeClock
+var MFAClock Clock = SystemClock
+
+// MFAStore 二次验证数据存储，未绑定时 Get 返回 gorm.ErrRecordNotFound
+type MFAStore interface {
+	Get(userID uint) (*models.UserMFA, error)
+	Save(mfa *models.UserMFA) error
+	Enable(userID uint, step int64, codeHashes []string) error
+	UseStep(userID uint, step int64) (bool, error)                           // 时间步不大于上次记录时返回 false
+	UseRecoveryCode(userID uint, codeHash string) (bool, error)              // 恢复码不存在或已使用时返回 false
+	UseChallenge(userID uint, jti string, expiresAt time.Time) (bool, error) // 挑战令牌已使用时返回 false
+}
+
+// dbMFAStore 基于数据库的二次验证存储
+type dbMFAStore struct{}
+
+func (dbMFAStore) Get(userID uint) (*models.UserMFA, error) {
+	return dao.GetUserMFA(userID)
+}
+
+func (dbMFAStore) Save(mfa *models.UserMFA) error {
+	return dao.SaveUserMFA(mfa)
+}
+
+func (dbMFAStore) Enable(userID uint, step int64, codeHashes []string) error {
+	return dao.EnableUserMFA(userID, step, codeHashes)
+}
+
+func (dbMFAStore) UseStep(userID uint, step int64) (bool, error) {
+	return dao.UseMFAStep(userID, step)
+}
+
+func (dbMFAStore) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	return dao.UseRecoveryCode(userID, codeHash)
+}
+
+func (dbMFAStore) UseChallenge(userID uint, jti string, expiresAt time.Time) (bool, error) {
+	return dao.UseMFAChallenge(userID, jti, expiresAt)
+}
+
+// MFAStorage 二次验证使用的存储，离线测试时可替换为内存实现
+var MFAStorage MFAStore = dbMFAStore{}
+
+// IssueMFAChallenge 按 MFAClock 签发二次验证挑战令牌
+func IssueMFAChallenge(userID uint) (string, error) {
+	return token.GenerateMFAChallengeTokenAt(userID, MFAClock.Now())
+}
+
+// MFAChallenge 已校验的二次验证挑战令牌
+type MFAChallenge struct {
+	UserID    uint
+	ID        string // jti
+	ExpiresAt time.Time
+}
+
+// ParseMFAChallenge 按 MFAClock 校验二次验证挑战令牌的签名、类型和有效期
+func ParseMFAChallenge(challenge string) (*MFAChallenge, error) {
+	claims, err := token.ParseTokenOfTypeAt(challenge, token.TypeMFAChallenge, MFAClock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, ErrMFAChallengeInvalid
+	}
+	return &MFAChallenge{UserID: claims.UserID, ID: claims.ID, ExpiresAt: claims.ExpiresAt.Time}, nil
+}
+
+// ConsumeMFAChallenge 将挑战令牌标记为已使用，重放时返回 ErrMFAChallengeUsed
+// 应在验证码校验通过后、签发访问令牌前调用，验证码输错不会消耗挑战令牌
+func ConsumeMFAChallenge(challenge *MFAChallenge) error {
+	fresh, err := MFAStorage.UseChallenge(challenge.UserID, challenge.ID, challenge.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrMFAChallengeUsed
+	}
+	return nil
+}
+
+// EnrollMFA 为用户生成新的 TOTP 密钥（尚未启用，需调用 ConfirmMFA 确认）
+// 返回密钥和用于生成二维码的 otpauth:// 链接
+func EnrollMFA(user *models.User) (string, string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := MFAStorage.Save(&models.UserMFA{UserID: user.ID, Secret: secret}); err != nil {
+		return "", "", err
+	}
+
+	return secret, totp.ProvisioningURI(mfaIssuer(), user.Username, secret), nil
+}
+
+// ConfirmMFA 校验验证器应用生成的验证码并启用二次验证，返回一次性恢复码明文
+func ConfirmMFA(userID uint, code string) ([]string, error) {
+	mfa, err := getMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := totp.Validate(mfa.Secret, code, MFAClock.Now(), mfaSkew)
+	if !ok {
+		return nil, ErrMFAInvalidCode
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, token.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := MFAStorage.Enable(userID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifyMFA 校验 TOTP 验证码或恢复码，二者都只能使用一次
+func VerifyMFA(userID uint, code string) error {
+	mfa, err := getMFA(userID)
+	if err != nil {
+		return err
+	}
+	if !mfa.Enabled {
+		return ErrMFANotEnrolled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(mfa.Secret, code, MFAClock.Now(), mfaSkew)
+		if !ok {
+			return ErrMFAInvalidCode
+		}
+		fresh, err := MFAStorage.UseStep(userID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrMFAInvalidCode
+		}
+		return nil
+	}
+
+	used, err := MFAStorage.UseRecoveryCode(userID, token.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrMFAInvalidCode
+	}
+	return nil
+}
+
+// MFAEnabled 判断用户是否已启用二次验证
+func MFAEnabled(userID uint) (bool, error) {
+	mfa, err := getMFA(userID)
+	if errors.Is(err, ErrMFANotEnrolled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return mfa.Enabled, nil
+}
+
+// getMFA 获取用户二次验证配置，未绑定时返回 ErrMFANotEnrolled
+func getMFA(userID uint) (*models.UserMFA, error) {
+	mfa, err := MFAStorage.Get(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMFANotEnrolled
+	}
+	return mfa, err
+}
+
+// generateRecoveryCode 生成形如 "abcde-fghij" 的恢复码
+func generateRecoveryCode() (string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+	code := strings.ToLower(secret[:10])
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空格和连字符
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// mfaIssuer 验证器应用中显示的发行方名称
+func mfaIssuer() string {
+	if issuer := viper.GetString("mfa.issuer"); issuer != "" {
+		return issuer
+	}
+	if name := viper.GetString("Server.Name"); name != "" {
+		return name
+	}
+	return "gin-data-visualization"
+}
@@ -0,0 +1,294 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-web/internal/models"
+	"go-web/pkg/token"
+	"go-web/pkg/totp"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// memoryMFAStore 测试用的内存二次验证存储
+type memoryMFAStore struct {
+	mu    sync.Mutex
+	items map[uint]*models.UserMFA
+	codes map[uint]map[string]bool // 恢复码哈希 -> 是否已使用
+	used  map[string]bool          // 已使用的挑战令牌 jti
+}
+
+func newMemoryMFAStore() *memoryMFAStore {
+	return &memoryMFAStore{
+		items: make(map[uint]*models.UserMFA),
+		codes: make(map[uint]map[string]bool),
+		used:  make(map[string]bool),
+	}
+}
+
+func (s *memoryMFAStore) Get(userID uint) (*models.UserMFA, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mfa, ok := s.items[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *mfa
+	return &copied, nil
+}
+
+func (s *memoryMFAStore) Save(mfa *models.UserMFA) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *mfa
+	s.items[mfa.UserID] = &copied
+	return nil
+}
+
+func (s *memoryMFAStore) Enable(userID uint, step int64, codeHashes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mfa := s.items[userID]
+	mfa.Enabled = true
+	mfa.LastUsedStep = step
+	s.codes[userID] = make(map[string]bool)
+	for _, hash := range codeHashes {
+		s.codes[userID][hash] = false
+	}
+	return nil
+}
+
+func (s *memoryMFAStore) UseStep(userID uint, step int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mfa := s.items[userID]
+	if mfa == nil || mfa.LastUsedStep >= step {
+		return false, nil
+	}
+	mfa.LastUsedStep = step
+	return true, nil
+}
+
+func (s *memoryMFAStore) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	used, ok := s.codes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	s.codes[userID][codeHash] = true
+	return true, nil
+}
+
+func (s *memoryMFAStore) UseChallenge(userID uint, jti string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.used[jti] {
+		return false, nil
+	}
+	s.used[jti] = true
+	return true, nil
+}
+
+// setupMFA 使用内存存储和假时钟，返回已启用二次验证的用户密钥和恢复码
+func setupMFA(t *testing.T) (*FakeClock, string, []string) {
+	t.Helper()
+
+	clock := NewFakeClock(time.Now().Truncate(time.Duration(totp.Period) * time.Second))
+	oldClock, oldStore := MFAClock, MFAStorage
+	MFAClock, MFAStorage = clock, newMemoryMFAStore()
+	t.Cleanup(func() { MFAClock, MFAStorage = oldClock, oldStore })
+
+	secret, _, err := EnrollMFA(&models.User{ID: 1, Username: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, err := ConfirmMFA(1, mustCode(t, secret, clock.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("恢复码数量 = %d", len(codes))
+	}
+	return clock, secret, codes
+}
+
+func mustCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := totp.GenerateCode(secret, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestVerifyMFASkewWindow(t *testing.T) {
+	clock, secret, _ := setupMFA(t)
+	period := time.Duration(totp.Period) * time.Second
+
+	tests := []struct {
+		name   string
+		offset time.Duration // 验证器应用时间与服务端时间的差
+		ok     bool
+	}{
+		{"时钟一致", 0, true},
+		{"验证器慢一个时间步", -period, true},
+		{"验证器快一个时间步", period, true},
+		{"验证器慢两个时间步", -2 * period, false},
+		{"验证器快两个时间步", 2 * period, false},
+	}
+	for _, tt := range tests {
+		// 每次推进足够多的时间步，避免与上一次使用的时间步冲突
+		clock.Advance(10 * period)
+		err := VerifyMFA(1, mustCode(t, secret, clock.Now().Add(tt.offset)))
+		if tt.ok && err != nil {
+			t.Errorf("%s: 意外错误 %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrMFAInvalidCode) {
+			t.Errorf("%s: err = %v, 期望 ErrMFAInvalidCode", tt.name, err)
+		}
+	}
+}
+
+func TestVerifyMFARejectsReplay(t *testing.T) {
+	clock, secret, _ := setupMFA(t)
+	period := time.Duration(totp.Period) * time.Second
+
+	// 确认绑定时使用的验证码不能再用于登录
+	if err := VerifyMFA(1, mustCode(t, secret, clock.Now())); !errors.Is(err, ErrMFAInvalidCode) {
+		t.Fatalf("绑定时的验证码被重放: %v", err)
+	}
+
+	clock.Advance(period)
+	code := mustCode(t, secret, clock.Now())
+	if err := VerifyMFA(1, code); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyMFA(1, code); !errors.Is(err, ErrMFAInvalidCode) {
+		t.Fatalf("同一验证码第二次使用: %v", err)
+	}
+
+	// 使用了较新的时间步后，偏差窗口内较旧的验证码也不能再用
+	clock.Advance(period)
+	if err := VerifyMFA(1, mustCode(t, secret, clock.Now().Add(period))); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyMFA(1, mustCode(t, secret, clock.Now())); !errors.Is(err, ErrMFAInvalidCode) {
+		t.Fatalf("旧时间步的验证码被接受: %v", err)
+	}
+}
+
+func TestVerifyMFARecoveryCodes(t *testing.T) {
+	_, _, codes := setupMFA(t)
+
+	if err := VerifyMFA(1, codes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyMFA(1, codes[0]); !errors.Is(err, ErrMFAInvalidCode) {
+		t.Fatalf("恢复码第二次使用: %v", err)
+	}
+
+	// 忽略大小写、空格和连字符
+	formatted := strings.ToUpper(strings.ReplaceAll(codes[1], "-", " "))
+	if err := VerifyMFA(1, formatted); err != nil {
+		t.Fatalf("格式不同的恢复码: %v", err)
+	}
+	if err := VerifyMFA(1, "aaaaa-bbbbb"); !errors.Is(err, ErrMFAInvalidCode) {
+		t.Fatalf("错误的恢复码: %v", err)
+	}
+}
+
+func TestVerifyMFANotEnrolled(t *testing.T) {
+	setupMFA(t)
+	if err := VerifyMFA(2, "123456"); !errors.Is(err, ErrMFANotEnrolled) {
+		t.Fatalf("err = %v, 期望 ErrMFANotEnrolled", err)
+	}
+}
+
+// setupKeyring 使用测试密钥初始化令牌密钥环
+func setupKeyring(t *testing.T) {
+	t.Helper()
+	viper.Set("jwt.secret", "test-secret")
+	t.Cleanup(func() { viper.Set("jwt.secret", nil) })
+	if err := token.InitKeyring(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMFAChallengeExpiry(t *testing.T) {
+	clock, _, _ := setupMFA(t)
+	setupKeyring(t)
+
+	challenge, err := IssueMFAChallenge(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(token.MFAChallengeTTL - time.Second)
+	parsed, err := ParseMFAChallenge(challenge)
+	if err != nil || parsed.UserID != 1 {
+		t.Fatalf("有效期内: challenge = %+v, err = %v", parsed, err)
+	}
+
+	clock.Advance(2 * time.Second)
+	if _, err := ParseMFAChallenge(challenge); err == nil {
+		t.Fatal("过期的挑战令牌不应通过")
+	}
+
+	// 访问令牌不能当作挑战令牌使用
+	access, err := token.GenerateAccessToken(1, "sid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := token.ParseTokenOfType(access, token.TypeMFAChallenge); err == nil {
+		t.Fatal("访问令牌不应被当作挑战令牌")
+	}
+}
+
+func TestMFAChallengeSingleUse(t *testing.T) {
+	setupMFA(t)
+	setupKeyring(t)
+
+	first, err := IssueMFAChallenge(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := IssueMFAChallenge(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challenge, err := ParseMFAChallenge(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ConsumeMFAChallenge(challenge); err != nil {
+		t.Fatalf("首次兑换失败: %v", err)
+	}
+
+	// 重放同一挑战令牌：签名和有效期仍然有效，但不能再次兑换
+	replayed, err := ParseMFAChallenge(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ConsumeMFAChallenge(replayed); !errors.Is(err, ErrMFAChallengeUsed) {
+		t.Fatalf("err = %v, 期望 ErrMFAChallengeUsed", err)
+	}
+
+	// 每次登录签发的挑战令牌相互独立
+	other, err := ParseMFAChallenge(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID == challenge.ID {
+		t.Fatal("挑战令牌的 jti 应各不相同")
+	}
+	if err := ConsumeMFAChallenge(other); err != nil {
+		t.Fatalf("另一个挑战令牌应可兑换: %v", err)
+	}
+}
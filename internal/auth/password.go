@@ -0,0 +1,372 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"go-web/internal/dao"
+	"go-web/pkg/logger"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 密码策略错误
+var (
+	ErrPasswordFormat      = errors.New("密码格式无效，应为 SHA-256 哈希值")
+	ErrPasswordPrehashed   = errors.New("设置密码时需提交明文密码")
+	ErrPasswordTooShort    = errors.New("密码长度不足")
+	ErrPasswordTooLong     = errors.New("密码长度超出限制")
+	ErrPasswordCharClasses = errors.New("密码未包含要求的字符类别")
+	ErrPasswordBreached    = errors.New("密码出现在已泄露密码列表中")
+	ErrPasswordReused      = errors.New("不能使用最近用过的密码")
+)
+
+// 密码字符类别
+const (
+	CharClassLower  = "lower"
+	CharClassUpper  = "upper"
+	CharClassDigit  = "digit"
+	CharClassSymbol = "symbol"
+)
+
+// charClassNames 字符类别的中文名称，用于错误提示
+var charClassNames = map[string]string{
+	CharClassLower:  "小写字母",
+	CharClassUpper:  "大写字母",
+	CharClassDigit:  "数字",
+	CharClassSymbol: "特殊符号",
+}
+
+// PasswordPolicy 密码策略，对应 security.password_policy 配置
+//
+// 服务端保存的是密码 SHA-256 哈希（64位十六进制，即客户端哈希）的 bcrypt 值。
+// 客户端通过 password_format 声明提交的是明文还是客户端哈希：明文由服务端计算 SHA-256 后使用，
+// 设置密码（注册、重置、修改）时先校验长度和字符类别。
+// 客户端哈希无法校验长度和字符类别，设置密码时仅在 AllowClientHash 开启（旧版客户端迁移期间）时接受，
+// 默认拒绝，不会因为客户端提交哈希而静默放宽策略。
+type PasswordPolicy struct {
+	MinLength        int      `mapstructure:"min_length"`
+	MaxLength        int      `mapstructure:"max_length"`
+	RequiredClasses  []string `mapstructure:"required_classes"`
+	BreachedListFile string   `mapstructure:"breached_list_file"`
+	HistorySize      int      `mapstructure:"history_size"` // 禁止重复使用最近 N 个密码（含当前密码），0 表示不限制
+	AllowClientHash  bool     `mapstructure:"allow_client_hash"`
+
+	breached map[string]struct{} // 泄露密码的 SHA-256 哈希
+}
+
+var (
+	policyMu sync.RWMutex
+	policy   = defaultPasswordPolicy()
+)
+
+// defaultPasswordPolicy 未配置时使用的默认策略
+func defaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength: 8,
+		MaxLength: 128,
+		breached:  map[string]struct{}{},
+	}
+}
+
+// InitPasswordPolicy 根据配置加载密码策略和泄露密码列表
+func InitPasswordPolicy() error {
+	p := defaultPasswordPolicy()
+	if err := viper.UnmarshalKey("security.password_policy", p); err != nil {
+		return err
+	}
+
+	if p.MinLength <= 0 {
+		p.MinLength = 8
+	}
+	if p.MaxLength <= 0 {
+		p.MaxLength = 128
+	}
+	if p.MaxLength < p.MinLength {
+		return fmt.Errorf("password_policy.max_length (%d) 小于 min_length (%d)", p.MaxLength, p.MinLength)
+	}
+	if p.HistorySize < 0 {
+		p.HistorySize = 0
+	}
+	for i, class := range p.RequiredClasses {
+		class = strings.ToLower(strings.TrimSpace(class))
+		if _, ok := charClassNames[class]; !ok {
+			return fmt.Errorf("password_policy.required_classes 包含未知类别: %s", class)
+		}
+		p.RequiredClasses[i] = class
+	}
+
+	if p.BreachedListFile != "" {
+		breached, err := loadBreachedList(p.BreachedListFile)
+		if err != nil {
+			return fmt.Errorf("加载泄露密码列表失败: %w", err)
+		}
+		p.breached = breached
+	}
+
+	policyMu.Lock()
+	policy = p
+	policyMu.Unlock()
+
+	logger.Infow("密码策略已加载",
+		"min_length", p.MinLength,
+		"required_classes", p.RequiredClasses,
+		"breached_entries", len(p.breached),
+		"history_size", p.HistorySize,
+		"bcrypt_cost", BcryptCost())
+	return nil
+}
+
+// CurrentPasswordPolicy 返回当前生效的密码策略
+func CurrentPasswordPolicy() *PasswordPolicy {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	return policy
+}
+
+// loadBreachedList 读取泄露密码列表（每行一个明文密码，# 开头为注释），保存为 SHA-256 哈希
+func loadBreachedList(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	breached := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		breached[ClientHash(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return breached, nil
+}
+
+// BcryptCost 密码加密使用的 bcrypt 代价（security.bcrypt_cost），超出范围时使用默认值
+func BcryptCost() int {
+	cost := viper.GetInt("security.bcrypt_cost")
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return bcrypt.DefaultCost
+	}
+	return cost
+}
+
+// ClientHash 计算明文密码的客户端哈希（SHA-256 十六进制小写），与前端登录时的算法一致
+func ClientHash(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// isClientHash 判断是否为 64 位十六进制的 SHA-256 哈希
+func isClientHash(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// NormalizeClientHash 校验客户端提交的 SHA-256 哈希并统一为小写
+func NormalizeClientHash(s string) (string, error) {
+	if !isClientHash(s) {
+		return "", ErrPasswordFormat
+	}
+	return strings.ToLower(s), nil
+}
+
+// LoginClientHash 将登录或验证原密码时提交的密码转换为客户端哈希，prehashed 表示提交的已是客户端哈希
+func LoginClientHash(password string, prehashed bool) (string, error) {
+	if prehashed {
+		return NormalizeClientHash(password)
+	}
+	return ClientHash(password), nil
+}
+
+// HashPassword 使用配置的 bcrypt 代价加密客户端哈希
+func HashPassword(clientHash string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(clientHash), BcryptCost())
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// CheckPassword 校验客户端哈希与保存的 bcrypt 哈希是否匹配
+func CheckPassword(passwordHash, clientHash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(clientHash)) == nil
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// SimulatePasswordCheck 用户不存在时执行一次等价的 bcrypt 校验，使响应耗时与密码错误时一致
+func SimulatePasswordCheck(clientHash string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte(ClientHash("dummy-password")), BcryptCost())
+	})
+	bcrypt.CompareHashAndPassword(dummyHash, []byte(clientHash))
+}
+
+// NeedsRehash 判断保存的哈希是否低于当前配置的 bcrypt 代价
+func NeedsRehash(passwordHash string) bool {
+	cost, err := bcrypt.Cost([]byte(passwordHash))
+	if err != nil {
+		return false
+	}
+	return cost < BcryptCost()
+}
+
+// PreparePassword 按密码策略校验新密码，返回待保存的 bcrypt 哈希
+// prehashed 表示提交的是客户端哈希；userID 为0（新注册用户）时不检查历史密码
+func PreparePassword(userID uint, input string, prehashed bool) (string, error) {
+	p := CurrentPasswordPolicy()
+
+	var clientHash string
+	if prehashed {
+		if !p.AllowClientHash {
+			return "", ErrPasswordPrehashed
+		}
+		var err error
+		if clientHash, err = NormalizeClientHash(input); err != nil {
+			return "", err
+		}
+	} else {
+		if err := p.checkComposition(input); err != nil {
+			return "", err
+		}
+		clientHash = ClientHash(input)
+	}
+
+	if _, ok := p.breached[clientHash]; ok {
+		return "", ErrPasswordBreached
+	}
+
+	if userID != 0 && p.HistorySize > 0 {
+		reused, err := passwordReused(userID, clientHash, p.HistorySize)
+		if err != nil {
+			return "", err
+		}
+		if reused {
+			return "", ErrPasswordReused
+		}
+	}
+
+	return HashPassword(clientHash)
+}
+
+// PasswordHistoryKeep 修改密码时需保留的历史密码数量（当前密码不计入历史表）
+func PasswordHistoryKeep() int {
+	if n := CurrentPasswordPolicy().HistorySize; n > 1 {
+		return n - 1
+	}
+	return 0
+}
+
+// checkComposition 检查明文密码的长度和字符类别
+func (p *PasswordPolicy) checkComposition(plain string) error {
+	length := utf8.RuneCountInString(plain)
+	if length < p.MinLength {
+		return ErrPasswordTooShort
+	}
+	if length > p.MaxLength {
+		return ErrPasswordTooLong
+	}
+
+	present := make(map[string]bool, len(charClassNames))
+	for _, r := range plain {
+		switch {
+		case unicode.IsLower(r):
+			present[CharClassLower] = true
+		case unicode.IsUpper(r):
+			present[CharClassUpper] = true
+		case unicode.IsDigit(r):
+			present[CharClassDigit] = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || r == ' ':
+			present[CharClassSymbol] = true
+		}
+	}
+	for _, class := range p.RequiredClasses {
+		if !present[class] {
+			return ErrPasswordCharClasses
+		}
+	}
+	return nil
+}
+
+// Requirement 描述密码策略要求，用于错误提示
+func (p *PasswordPolicy) Requirement() string {
+	desc := fmt.Sprintf("密码长度需为 %d-%d 位", p.MinLength, p.MaxLength)
+	if len(p.RequiredClasses) > 0 {
+		names := make([]string, 0, len(p.RequiredClasses))
+		for _, class := range p.RequiredClasses {
+			names = append(names, charClassNames[class])
+		}
+		desc += "，且必须包含" + strings.Join(names, "、")
+	}
+	return desc
+}
+
+// PasswordHistoryStore 历史密码查询，用于禁止重复使用最近的密码
+type PasswordHistoryStore interface {
+	CurrentHash(userID uint) (string, error)         // 用户当前密码的 bcrypt 哈希
+	Recent(userID uint, limit int) ([]string, error) // 最近 limit 个历史密码哈希
+}
+
+// dbPasswordHistoryStore 基于数据库的历史密码查询
+type dbPasswordHistoryStore struct{}
+
+func (dbPasswordHistoryStore) CurrentHash(userID uint) (string, error) {
+	user, err := dao.GetUserByID(userID)
+	if err != nil {
+		return "", err
+	}
+	return user.PasswordHash, nil
+}
+
+func (dbPasswordHistoryStore) Recent(userID uint, limit int) ([]string, error) {
+	return dao.ListPasswordHistory(userID, limit)
+}
+
+// PasswordHistories 历史密码检查使用的存储，离线测试时可替换为内存实现
+var PasswordHistories PasswordHistoryStore = dbPasswordHistoryStore{}
+
+// passwordReused 检查客户端哈希是否与当前密码或最近的历史密码相同
+func passwordReused(userID uint, clientHash string, historySize int) (bool, error) {
+	current, err := PasswordHistories.CurrentHash(userID)
+	if err != nil {
+		return false, err
+	}
+	if CheckPassword(current, clientHash) {
+		return true, nil
+	}
+
+	if historySize <= 1 {
+		return false, nil
+	}
+	hashes, err := PasswordHistories.Recent(userID, historySize-1)
+	if err != nil {
+		return false, err
+	}
+	for _, hash := range hashes {
+		if CheckPassword(hash, clientHash) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
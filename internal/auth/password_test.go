@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-web/pkg/logger"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memoryPasswordHistory 测试用的内存历史密码存储
+type memoryPasswordHistory struct {
+	mu      sync.Mutex
+	current map[uint]string
+	history map[uint][]string // 按替换时间倒序
+}
+
+func newMemoryPasswordHistory() *memoryPasswordHistory {
+	return &memoryPasswordHistory{current: make(map[uint]string), history: make(map[uint][]string)}
+}
+
+func (s *memoryPasswordHistory) CurrentHash(userID uint) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current[userID], nil
+}
+
+func (s *memoryPasswordHistory) Recent(userID uint, limit int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hashes := s.history[userID]
+	if len(hashes) > limit {
+		hashes = hashes[:limit]
+	}
+	return hashes, nil
+}
+
+// change 模拟修改密码：当前密码移入历史
+func (s *memoryPasswordHistory) change(userID uint, passwordHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.current[userID]; ok {
+		s.history[userID] = append([]string{current}, s.history[userID]...)
+	}
+	s.current[userID] = passwordHash
+}
+
+// usePolicy 按给定配置加载密码策略，测试结束后恢复原策略
+func usePolicy(t *testing.T, config map[string]interface{}) {
+	t.Helper()
+
+	logger.Sugar = zap.NewNop().Sugar()
+	old := CurrentPasswordPolicy()
+	viper.Set("security.password_policy", config)
+	viper.Set("security.bcrypt_cost", bcrypt.MinCost)
+	t.Cleanup(func() {
+		viper.Set("security.password_policy", nil)
+		viper.Set("security.bcrypt_cost", nil)
+		policyMu.Lock()
+		policy = old
+		policyMu.Unlock()
+	})
+
+	if err := InitPasswordPolicy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPasswordPolicyClientHash(t *testing.T) {
+	usePolicy(t, map[string]interface{}{"required_classes": []string{"lower", "upper", "digit"}})
+	prehashed := ClientHash("whatever")
+
+	if CurrentPasswordPolicy().AllowClientHash {
+		t.Fatal("默认不应接受客户端哈希")
+	}
+	if _, err := PreparePassword(0, prehashed, true); !errors.Is(err, ErrPasswordPrehashed) {
+		t.Fatalf("err = %v, 期望 ErrPasswordPrehashed", err)
+	}
+
+	// 明文格式提交的64位十六进制字符串按明文处理，照常校验字符类别
+	if _, err := PreparePassword(0, prehashed, false); !errors.Is(err, ErrPasswordCharClasses) {
+		t.Fatalf("err = %v, 期望 ErrPasswordCharClasses", err)
+	}
+
+	usePolicy(t, map[string]interface{}{"allow_client_hash": true})
+	hashed, err := PreparePassword(0, strings.ToUpper(prehashed), true)
+	if err != nil {
+		t.Fatalf("开启后客户端哈希应被接受: %v", err)
+	}
+	if !CheckPassword(hashed, prehashed) {
+		t.Fatal("客户端哈希应统一为小写后加密")
+	}
+	if _, err := PreparePassword(0, "not-a-hash", true); !errors.Is(err, ErrPasswordFormat) {
+		t.Fatalf("err = %v, 期望 ErrPasswordFormat", err)
+	}
+}
+
+func TestLoginClientHash(t *testing.T) {
+	plain := "Password1"
+	fromPlain, err := LoginClientHash(plain, false)
+	if err != nil || fromPlain != ClientHash(plain) {
+		t.Fatalf("明文: %q, %v", fromPlain, err)
+	}
+	fromHash, err := LoginClientHash(strings.ToUpper(ClientHash(plain)), true)
+	if err != nil || fromHash != fromPlain {
+		t.Fatalf("客户端哈希: %q, %v", fromHash, err)
+	}
+	if _, err := LoginClientHash(plain, true); !errors.Is(err, ErrPasswordFormat) {
+		t.Fatalf("err = %v, 期望 ErrPasswordFormat", err)
+	}
+}
+
+func TestPasswordPolicyComposition(t *testing.T) {
+	usePolicy(t, map[string]interface{}{
+		"min_length":       10,
+		"max_length":       20,
+		"required_classes": []string{"Lower", "upper", "digit", "symbol"},
+	})
+
+	tests := []struct {
+		password string
+		wantErr  error
+	}{
+		{password: "Aa1!aaaaaa"},
+		{password: "Aa1 aaaaaa"},
+		{password: "Ää1!ääääää"},
+		{password: "Aa1!aaaaa", wantErr: ErrPasswordTooShort},
+		{password: "Aa1!aaaaaaaaaaaaaaaaa", wantErr: ErrPasswordTooLong},
+		{password: "aa1!aaaaaa", wantErr: ErrPasswordCharClasses},
+		{password: "AA1!AAAAAA", wantErr: ErrPasswordCharClasses},
+		{password: "Aab!aaaaaa", wantErr: ErrPasswordCharClasses},
+		{password: "Aa1aaaaaaa", wantErr: ErrPasswordCharClasses},
+	}
+	for _, tt := range tests {
+		_, err := PreparePassword(0, tt.password, false)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("PreparePassword(%q) err = %v, 期望 %v", tt.password, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPasswordPolicyRejectsUnknownClass(t *testing.T) {
+	logger.Sugar = zap.NewNop().Sugar()
+	viper.Set("security.password_policy", map[string]interface{}{"required_classes": []string{"emoji"}})
+	t.Cleanup(func() { viper.Set("security.password_policy", nil) })
+
+	if err := InitPasswordPolicy(); err == nil {
+		t.Fatal("未知字符类别应导致加载失败")
+	}
+}
+
+func TestPasswordPolicyBreachedList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte("# 常见密码\nPassword123\r\n\nQwerty12345\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	usePolicy(t, map[string]interface{}{"breached_list_file": path, "allow_client_hash": true})
+
+	for _, password := range []string{"Password123", "Qwerty12345"} {
+		if _, err := PreparePassword(0, password, false); !errors.Is(err, ErrPasswordBreached) {
+			t.Errorf("PreparePassword(%q) err = %v, 期望 ErrPasswordBreached", password, err)
+		}
+	}
+	if _, err := PreparePassword(0, ClientHash("Password123"), true); !errors.Is(err, ErrPasswordBreached) {
+		t.Errorf("客户端哈希 err = %v, 期望 ErrPasswordBreached", err)
+	}
+	if _, err := PreparePassword(0, "# 常见密码", false); errors.Is(err, ErrPasswordBreached) {
+		t.Error("注释行不应计入泄露列表")
+	}
+	if _, err := PreparePassword(0, "Password1234", false); err != nil {
+		t.Errorf("不在列表中的密码应被接受: %v", err)
+	}
+}
+
+func TestPasswordPolicyHistory(t *testing.T) {
+	usePolicy(t, map[string]interface{}{"history_size": 3})
+	store := newMemoryPasswordHistory()
+	old := PasswordHistories
+	PasswordHistories = store
+	t.Cleanup(func() { PasswordHistories = old })
+
+	// 依次使用 p1..p4，当前密码为 p4，历史为 p3、p2、p1
+	for _, password := range []string{"password-1", "password-2", "password-3", "password-4"} {
+		hash, err := PreparePassword(1, password, false)
+		if err != nil {
+			t.Fatalf("设置 %s 失败: %v", password, err)
+		}
+		store.change(1, hash)
+	}
+
+	// 最近 3 个（含当前密码）不能重复使用，更早的可以
+	for _, password := range []string{"password-4", "password-3", "password-2"} {
+		if _, err := PreparePassword(1, password, false); !errors.Is(err, ErrPasswordReused) {
+			t.Errorf("PreparePassword(%q) err = %v, 期望 ErrPasswordReused", password, err)
+		}
+	}
+	if _, err := PreparePassword(1, "password-1", false); err != nil {
+		t.Errorf("超出历史范围的密码应被接受: %v", err)
+	}
+	if _, err := PreparePassword(0, "password-4", false); err != nil {
+		t.Errorf("新注册用户不检查历史密码: %v", err)
+	}
+	if keep := PasswordHistoryKeep(); keep != 2 {
+		t.Errorf("PasswordHistoryKeep() = %d, 期望 2", keep)
+	}
+}
+
+func TestPasswordCostUpgrade(t *testing.T) {
+	usePolicy(t, map[string]interface{}{})
+	clientHash := ClientHash("Password1")
+
+	stored, err := HashPassword(clientHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if NeedsRehash(stored) {
+		t.Fatal("当前代价生成的哈希不需要升级")
+	}
+
+	// 提高配置的代价后，旧哈希仍可登录，且需要在登录时升级
+	viper.Set("security.bcrypt_cost", bcrypt.MinCost+1)
+	if !CheckPassword(stored, clientHash) {
+		t.Fatal("旧哈希应仍可验证")
+	}
+	if !NeedsRehash(stored) {
+		t.Fatal("低于配置代价的哈希需要升级")
+	}
+
+	upgraded, err := HashPassword(clientHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost, _ := bcrypt.Cost([]byte(upgraded)); cost != bcrypt.MinCost+1 {
+		t.Fatalf("升级后的代价 = %d", cost)
+	}
+	if NeedsRehash(upgraded) || !CheckPassword(upgraded, clientHash) {
+		t.Fatal("升级后的哈希应可验证且不再需要升级")
+	}
+
+	// 配置超出范围时回退到默认代价
+	viper.Set("security.bcrypt_cost", 100)
+	if BcryptCost() != bcrypt.DefaultCost {
+		t.Fatalf("BcryptCost() = %d, 期望默认值", BcryptCost())
+	}
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"go-web/internal/dao"
+)
+
+// permissionCacheTTL 角色权限缓存时间，修改数据库中的权限后最多延迟这么久生效
+const permissionCacheTTL = time.Minute
+
+type cachedPermissions struct {
+	permissions map[string]struct{}
+	loadedAt    time.Time
+}
+
+var (
+	permissionMu    sync.RWMutex
+	permissionCache = make(map[string]cachedPermissions)
+)
+
+// RolePermissions 获取角色拥有的权限集合（带缓存）
+func RolePermissions(role string) (map[string]struct{}, error) {
+	permissionMu.RLock()
+	cached, ok := permissionCache[role]
+	permissionMu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < permissionCacheTTL {
+		return cached.permissions, nil
+	}
+
+	names, err := dao.GetRolePermissions(role)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		permissions[name] = struct{}{}
+	}
+
+	permissionMu.Lock()
+	permissionCache[role] = cachedPermissions{permissions: permissions, loadedAt: time.Now()}
+	permissionMu.Unlock()
+
+	return permissions, nil
+}
+
+// HasPermissions 判断角色是否拥有全部指定权限
+func HasPermissions(role string, required ...string) (bool, error) {
+	permissions, err := RolePermissions(role)
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range required {
+		if _, ok := permissions[name]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"go-web/internal/dao"
+	"go-web/pkg/logger"
+	"go-web/pkg/token"
+)
+
+// RevocationStore 已吊销会话族的内存缓存
+// 本实例吊销的会话会立即写入缓存，其他实例吊销的会话通过定期同步数据库获得
+type RevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time // 会话族ID -> 缓存过期时间（此后该会话的访问令牌必然已过期）
+}
+
+// Revocations 全局吊销缓存
+var Revocations = NewRevocationStore()
+
+// NewRevocationStore 创建吊销缓存
+func NewRevocationStore() *RevocationStore {
+	return &RevocationStore{revoked: make(map[string]time.Time)}
+}
+
+// Revoke 将会话族加入吊销缓存
+func (s *RevocationStore) Revoke(familyID string) {
+	s.add(familyID, time.Now())
+}
+
+// IsRevoked 判断会话族是否已吊销
+func (s *RevocationStore) IsRevoked(familyID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	until, ok := s.revoked[familyID]
+	return ok && time.Now().Before(until)
+}
+
+// Sync 从数据库同步最近被吊销的会话族，并清理已过期的缓存条目
+func (s *RevocationStore) Sync() error {
+	ttl := token.AccessTokenTTL()
+	families, err := dao.ListRevokedSessionFamilies(time.Now().Add(-ttl))
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		s.add(family.FamilyID, family.RevokedAt)
+	}
+
+	s.prune()
+	return nil
+}
+
+// StartSync 启动后台定期同步，返回停止函数
+func (s *RevocationStore) StartSync(interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Sync(); err != nil {
+					logger.Errorw("同步会话吊销列表失败", "error", err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// add 记录会话族在 revokedAt 时被吊销，缓存保留一个访问令牌有效期
+func (s *RevocationStore) add(familyID string, revokedAt time.Time) {
+	until := revokedAt.Add(token.AccessTokenTTL())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.revoked[familyID]; !ok || until.After(current) {
+		s.revoked[familyID] = until
+	}
+}
+
+// prune 清理已过期的缓存条目
+func (s *RevocationStore) prune() {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for familyID, until := range s.revoked {
+		if now.After(until) {
+			delete(s.revoked, familyID)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+	"go-web/pkg/token"
+	"go-web/pkg/useragent"
+
+	"gorm.io/gorm"
+)
+
+// sessionTouchInterval 会话最近活跃时间的最小记录间隔，避免每个请求都写库
+const sessionTouchInterval = time.Minute
+
+var (
+	sessionTouchMu sync.Mutex
+	sessionTouched = make(map[string]time.Time)
+)
+
+// 刷新令牌错误
+var (
+	ErrRefreshTokenInvalid = errors.New("刷新令牌无效")
+	ErrRefreshTokenRevoked = errors.New("刷新令牌已失效")
+	ErrRefreshTokenExpired = errors.New("刷新令牌已过期")
+	ErrRefreshTokenReused  = errors.New("刷新令牌已被使用")
+)
+
+// SessionStore 刷新令牌轮换使用的会话存储
+type SessionStore interface {
+	GetByRefreshToken(refreshTokenHash string) (*models.UserSession, error)
+	Rotate(old *models.UserSession, next *models.UserSession) error // 旧会话已轮换或吊销时返回 dao.ErrSessionRotated
+	RevokeFamily(familyID string, reason string) error
+}
+
+// dbSessionStore 基于数据库的会话存储
+type dbSessionStore struct{}
+
+func (dbSessionStore) GetByRefreshToken(refreshTokenHash string) (*models.UserSession, error) {
+	return dao.GetSessionByRefreshToken(refreshTokenHash)
+}
+
+func (dbSessionStore) Rotate(old *models.UserSession, next *models.UserSession) error {
+	return dao.RotateSession(old, next)
+}
+
+func (dbSessionStore) RevokeFamily(familyID string, reason string) error {
+	return dao.RevokeSessionFamily(familyID, reason)
+}
+
+// Sessions 会话存储，离线测试时可替换为内存实现
+var Sessions SessionStore = dbSessionStore{}
+
+// LookupRefreshSession 根据刷新令牌查找可轮换的会话
+// 已轮换的旧令牌被再次使用时视为令牌泄露，吊销整个会话族并返回 ErrRefreshTokenReused（同时返回该会话）
+func LookupRefreshSession(refreshToken string) (*models.UserSession, error) {
+	session, err := Sessions.GetByRefreshToken(token.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRefreshTokenInvalid
+		}
+		return nil, fmt.Errorf("%w: %v", ErrRefreshTokenInvalid, err)
+	}
+
+	if session.RevokedAt != nil {
+		if session.RevokeReason == models.SessionRevokeRotated {
+			return session, revokeReusedFamily(session)
+		}
+		return session, ErrRefreshTokenRevoked
+	}
+
+	if time.Now().After(session.RefreshExpiresAt) {
+		return session, ErrRefreshTokenExpired
+	}
+	return session, nil
+}
+
+// RotateRefreshSession 用新会话替换旧会话，旧刷新令牌立即作废
+// 并发请求抢先轮换了同一个刷新令牌时同样按重复使用处理
+func RotateRefreshSession(old *models.UserSession, next *models.UserSession) error {
+	if err := Sessions.Rotate(old, next); err != nil {
+		if errors.Is(err, dao.ErrSessionRotated) {
+			return revokeReusedFamily(old)
+		}
+		return err
+	}
+	return nil
+}
+
+// revokeReusedFamily 检测到刷新令牌重复使用时吊销整个会话族，吊销失败只记录日志
+func revokeReusedFamily(session *models.UserSession) error {
+	logger.Warnw("检测到刷新令牌重复使用，吊销会话族",
+		"user_id", session.UserID,
+		"family_id", session.FamilyID)
+
+	if err := RevokeSession(session.FamilyID, models.SessionRevokeReused); err != nil {
+		logger.Errorw("吊销会话族失败", "error", err, "family_id", session.FamilyID)
+	}
+	return ErrRefreshTokenReused
+}
+
+// RevokeSession 吊销单个会话族，并立即写入吊销缓存
+func RevokeSession(familyID string, reason string) error {
+	if err := Sessions.RevokeFamily(familyID, reason); err != nil {
+		return err
+	}
+	Revocations.Revoke(familyID)
+	return nil
+}
+
+// RevokeUserSessions 吊销用户的所有会话族（exceptFamilyID 除外），并立即写入吊销缓存
+func RevokeUserSessions(userID uint, reason string, exceptFamilyID string) error {
+	familyIDs, err := dao.RevokeUserSessions(userID, reason, exceptFamilyID)
+	if err != nil {
+		return err
+	}
+	for _, familyID := range familyIDs {
+		Revocations.Revoke(familyID)
+	}
+	return nil
+}
+
+// RevokeUserSession 吊销属于指定用户的单个会话族，会话不存在、不属于该用户或已吊销时返回 false
+func RevokeUserSession(userID uint, familyID string, reason string) (bool, error) {
+	owned, err := dao.UserOwnsSessionFamily(userID, familyID)
+	if err != nil || !owned {
+		return false, err
+	}
+	if err := RevokeSession(familyID, reason); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListSessions 获取用户的有效会话列表，currentFamilyID 对应的会话标记为当前会话
+func ListSessions(userID uint, currentFamilyID string) ([]models.SessionInfo, error) {
+	sessions, err := dao.ListActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		// 刷新令牌时会生成新行，因此最近活跃时间至少是当前行的创建时间
+		lastSeen := session.CreatedAt
+		if session.LastSeenAt != nil && session.LastSeenAt.After(lastSeen) {
+			lastSeen = *session.LastSeenAt
+		}
+
+		result = append(result, models.SessionInfo{
+			ID:         session.FamilyID,
+			Device:     useragent.Parse(session.UserAgent),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.StartedAt,
+			LastSeenAt: lastSeen,
+			ExpiresAt:  session.RefreshExpiresAt,
+			Current:    session.FamilyID == currentFamilyID,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].LastSeenAt.After(result[j].LastSeenAt) })
+	return result, nil
+}
+
+// TouchSession 记录会话族的最近活跃时间，同一会话族每分钟最多写库一次
+func TouchSession(familyID string) {
+	now := time.Now()
+
+	sessionTouchMu.Lock()
+	if last, ok := sessionTouched[familyID]; ok && now.Sub(last) < sessionTouchInterval {
+		sessionTouchMu.Unlock()
+		return
+	}
+	sessionTouched[familyID] = now
+	// 顺便清理长时间未活跃的记录
+	if len(sessionTouched) > 10000 {
+		for id, last := range sessionTouched {
+			if now.Sub(last) >= sessionTouchInterval {
+				delete(sessionTouched, id)
+			}
+		}
+	}
+	sessionTouchMu.Unlock()
+
+	if err := dao.TouchSession(familyID, now); err != nil {
+		logger.Errorw("记录会话活跃时间失败", "error", err, "family_id", familyID)
+	}
+}
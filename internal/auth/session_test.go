@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+	"go-web/pkg/token"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memorySessionStore 测试用的内存会话存储，轮换语义与 dao.RotateSession 一致
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions []*models.UserSession
+}
+
+func (s *memorySessionStore) GetByRefreshToken(refreshTokenHash string) (*models.UserSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, session := range s.sessions {
+		if session.RefreshToken == refreshTokenHash {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *memorySessionStore) Rotate(old *models.UserSession, next *models.UserSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, session := range s.sessions {
+		if session.ID != old.ID {
+			continue
+		}
+		if session.RevokedAt != nil {
+			return dao.ErrSessionRotated
+		}
+		next.ID = uint(len(s.sessions) + 1)
+		s.sessions = append(s.sessions, next)
+		now := time.Now()
+		session.RevokedAt = &now
+		session.RevokeReason = models.SessionRevokeRotated
+		session.ReplacedByID = &next.ID
+		return nil
+	}
+	return dao.ErrSessionRotated
+}
+
+func (s *memorySessionStore) RevokeFamily(familyID string, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for _, session := range s.sessions {
+		if session.FamilyID == familyID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			session.RevokeReason = reason
+		}
+	}
+	return nil
+}
+
+// setupSessions 使用内存会话存储和独立的吊销缓存，返回已有一个会话的存储
+func setupSessions(t *testing.T, refreshToken string) *memorySessionStore {
+	t.Helper()
+
+	logger.Sugar = zap.NewNop().Sugar()
+	store := &memorySessionStore{sessions: []*models.UserSession{{
+		ID:               1,
+		UserID:           7,
+		FamilyID:         "family-1",
+		RefreshToken:     token.HashToken(refreshToken),
+		RefreshExpiresAt: time.Now().Add(time.Hour),
+	}}}
+	oldStore, oldRevocations := Sessions, Revocations
+	Sessions, Revocations = store, NewRevocationStore()
+	t.Cleanup(func() { Sessions, Revocations = oldStore, oldRevocations })
+	return store
+}
+
+// nextSession 构造轮换后的新会话
+func nextSession(old *models.UserSession, refreshToken string) *models.UserSession {
+	return &models.UserSession{
+		UserID:           old.UserID,
+		FamilyID:         old.FamilyID,
+		RefreshToken:     token.HashToken(refreshToken),
+		RefreshExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	setupSessions(t, "refresh-1")
+
+	session, err := LookupRefreshSession("refresh-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RotateRefreshSession(session, nextSession(session, "refresh-2")); err != nil {
+		t.Fatal(err)
+	}
+	if Revocations.IsRevoked("family-1") {
+		t.Fatal("正常轮换不应吊销会话族")
+	}
+
+	// 重放已轮换的旧刷新令牌
+	replayed, err := LookupRefreshSession("refresh-1")
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("err = %v, 期望 ErrRefreshTokenReused", err)
+	}
+	if replayed == nil || replayed.FamilyID != "family-1" {
+		t.Fatalf("重复使用时应返回被重放的会话: %+v", replayed)
+	}
+	if !Revocations.IsRevoked("family-1") {
+		t.Fatal("会话族应立即写入吊销缓存")
+	}
+
+	// 轮换得到的新刷新令牌随会话族一起失效
+	current, err := LookupRefreshSession("refresh-2")
+	if !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("err = %v, 期望 ErrRefreshTokenRevoked", err)
+	}
+	if current.RevokeReason != models.SessionRevokeReused {
+		t.Fatalf("吊销原因 = %s, 期望 %s", current.RevokeReason, models.SessionRevokeReused)
+	}
+}
+
+func TestRefreshTokenConcurrentRotation(t *testing.T) {
+	setupSessions(t, "refresh-1")
+
+	// 两个请求同时查到同一个未轮换的会话
+	first, err := LookupRefreshSession("refresh-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LookupRefreshSession("refresh-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RotateRefreshSession(first, nextSession(first, "refresh-2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := RotateRefreshSession(second, nextSession(second, "refresh-3")); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("err = %v, 期望 ErrRefreshTokenReused", err)
+	}
+	if _, err := LookupRefreshSession("refresh-2"); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("抢先轮换得到的令牌应被吊销: %v", err)
+	}
+	if _, err := LookupRefreshSession("refresh-3"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("轮换失败时不应写入新会话: %v", err)
+	}
+}
+
+func TestLookupRefreshSessionErrors(t *testing.T) {
+	store := setupSessions(t, "refresh-1")
+
+	if _, err := LookupRefreshSession("unknown"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("未知令牌 err = %v", err)
+	}
+
+	store.sessions[0].RefreshExpiresAt = time.Now().Add(-time.Second)
+	if _, err := LookupRefreshSession("refresh-1"); !errors.Is(err, ErrRefreshTokenExpired) {
+		t.Errorf("过期令牌 err = %v", err)
+	}
+
+	// 登出等原因吊销的会话不按重复使用处理
+	if err := RevokeSession("family-1", models.SessionRevokeLogout); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LookupRefreshSession("refresh-1"); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Errorf("已吊销令牌 err = %v", err)
+	}
+}
@@ -0,0 +1,399 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/oidc"
+	"go-web/pkg/token"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOAuthProviderNotFound = errors.New("未配置该身份提供方")
+	ErrOAuthStateInvalid     = errors.New("登录请求无效或已过期")
+	ErrOAuthAccountNotLinked = errors.New("该外部账户未绑定本地用户")
+	ErrOAuthEmailInUse       = errors.New("邮箱已被本地账户使用")
+	ErrOAuthEmailMissing     = errors.New("身份提供方未返回邮箱")
+)
+
+// oauthProviderConfig oauth.providers 中单个身份提供方的配置
+type oauthProviderConfig struct {
+	Name            string   `mapstructure:"name"`
+	Issuer          string   `mapstructure:"issuer"`
+	ClientID        string   `mapstructure:"client_id"`
+	ClientSecret    string   `mapstructure:"client_secret"`
+	RedirectURL     string   `mapstructure:"redirect_url"`
+	Scopes          []string `mapstructure:"scopes"`
+	AutoCreate      bool     `mapstructure:"auto_create"`       // 首次登录时自动创建本地用户
+	LinkByEmail     bool     `mapstructure:"link_by_email"`     // 邮箱已验证且与本地用户一致时自动绑定
+	DefaultUserType string   `mapstructure:"default_user_type"` // 自动创建用户的类型，默认 app
+}
+
+// OAuthProvider 已初始化的身份提供方
+type OAuthProvider struct {
+	Name        string
+	client      *oidc.Provider
+	autoCreate  bool
+	linkByEmail bool
+	userType    models.UserType
+}
+
+// oauthPending 已发起、尚未完成的登录请求
+type oauthPending struct {
+	provider     string
+	codeVerifier string
+	nonce        string
+	expiresAt    time.Time
+}
+
+// OAuthUserStore 第三方登录映射本地用户时使用的存储，查询不到时返回 gorm.ErrRecordNotFound
+type OAuthUserStore interface {
+	GetIdentity(provider, subject string) (*models.UserIdentity, error)
+	TouchIdentity(id uint, email string) error
+	CreateIdentity(identity *models.UserIdentity) error
+	CreateUserWithIdentity(user *models.User, identity *models.UserIdentity) error
+	GetUserByID(id uint) (*models.User, error)
+	GetUserByEmail(email string) (*models.User, error)
+	UsernameTaken(username string) (bool, error)
+	EmailTaken(email string) (bool, error)
+}
+
+// dbOAuthUserStore 基于数据库的用户存储
+type dbOAuthUserStore struct{}
+
+func (dbOAuthUserStore) GetIdentity(provider, subject string) (*models.UserIdentity, error) {
+	return dao.GetUserIdentity(provider, subject)
+}
+
+func (dbOAuthUserStore) TouchIdentity(id uint, email string) error {
+	return dao.TouchUserIdentity(id, email)
+}
+
+func (dbOAuthUserStore) CreateIdentity(identity *models.UserIdentity) error {
+	return dao.CreateUserIdentity(identity)
+}
+
+func (dbOAuthUserStore) CreateUserWithIdentity(user *models.User, identity *models.UserIdentity) error {
+	return dao.CreateUserWithIdentity(user, identity)
+}
+
+func (dbOAuthUserStore) GetUserByID(id uint) (*models.User, error) {
+	return dao.GetUserByID(id)
+}
+
+func (dbOAuthUserStore) GetUserByEmail(email string) (*models.User, error) {
+	return dao.GetUserByEmail(email)
+}
+
+func (dbOAuthUserStore) UsernameTaken(username string) (bool, error) {
+	return dao.UsernameTaken(username)
+}
+
+func (dbOAuthUserStore) EmailTaken(email string) (bool, error) {
+	return dao.EmailTaken(email)
+}
+
+// OAuthUsers 第三方登录使用的用户存储，离线测试时可替换为内存实现
+var OAuthUsers OAuthUserStore = dbOAuthUserStore{}
+
+var (
+	oauthMu        sync.Mutex
+	oauthProviders = make(map[string]*OAuthProvider)
+	oauthStates    = make(map[string]oauthPending)
+)
+
+// InitOAuthProviders 根据 oauth 配置初始化身份提供方，oauth.enabled 为 false 时不启用任何提供方
+func InitOAuthProviders() error {
+	providers := make(map[string]*OAuthProvider)
+
+	if viper.GetBool("oauth.enabled") {
+		var configs []oauthProviderConfig
+		if err := viper.UnmarshalKey("oauth.providers", &configs); err != nil {
+			return fmt.Errorf("oauth.providers 配置格式错误: %v", err)
+		}
+
+		for _, cfg := range configs {
+			if cfg.Name == "" || cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+				return fmt.Errorf("身份提供方 %q 缺少 name/issuer/client_id/redirect_url", cfg.Name)
+			}
+			if _, exists := providers[cfg.Name]; exists {
+				return fmt.Errorf("身份提供方名称重复: %s", cfg.Name)
+			}
+
+			userType := models.UserType(cfg.DefaultUserType)
+			switch userType {
+			case "":
+				userType = models.UserTypeApp
+			case models.UserTypeApp, models.UserTypeSystem:
+			default:
+				return fmt.Errorf("身份提供方 %s 的 default_user_type 无效: %s", cfg.Name, cfg.DefaultUserType)
+			}
+
+			providers[cfg.Name] = &OAuthProvider{
+				Name: cfg.Name,
+				client: oidc.NewProvider(oidc.Config{
+					Issuer:       cfg.Issuer,
+					ClientID:     cfg.ClientID,
+					ClientSecret: cfg.ClientSecret,
+					RedirectURL:  cfg.RedirectURL,
+					Scopes:       cfg.Scopes,
+				}),
+				autoCreate:  cfg.AutoCreate,
+				linkByEmail: cfg.LinkByEmail,
+				userType:    userType,
+			}
+		}
+	}
+
+	oauthMu.Lock()
+	oauthProviders = providers
+	oauthMu.Unlock()
+	return nil
+}
+
+// OAuthProviderNames 返回已启用的身份提供方名称
+func OAuthProviderNames() []string {
+	oauthMu.Lock()
+	defer oauthMu.Unlock()
+
+	names := make([]string, 0, len(oauthProviders))
+	for name := range oauthProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginOAuthLogin 发起授权码 + PKCE 登录，返回跳转到身份提供方的地址和 state
+func BeginOAuthLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, err := getOAuthProvider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := oidc.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	codeVerifier, err := oidc.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := provider.client.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	oauthMu.Lock()
+	for key, pending := range oauthStates {
+		if now.After(pending.expiresAt) {
+			delete(oauthStates, key)
+		}
+	}
+	oauthStates[state] = oauthPending{
+		provider:     providerName,
+		codeVerifier: codeVerifier,
+		nonce:        nonce,
+		expiresAt:    now.Add(OAuthStateTTL()),
+	}
+	oauthMu.Unlock()
+
+	return authURL, state, nil
+}
+
+// OAuthStateBinding 返回与 state 对应的浏览器绑定值（state 的哈希），保存在发起登录的浏览器的 Cookie 中
+func OAuthStateBinding(state string) string {
+	return token.HashToken(state)
+}
+
+// OAuthStateBound 判断回调请求携带的绑定值是否与 state 对应，防止攻击者诱导受害者提交攻击者的授权码（登录 CSRF）
+func OAuthStateBound(state, binding string) bool {
+	if state == "" || binding == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(OAuthStateBinding(state)), []byte(binding)) == 1
+}
+
+// OAuthStateTTL 登录请求的有效期
+func OAuthStateTTL() time.Duration {
+	return durationOr("oauth.state_expire", 10*time.Minute)
+}
+
+// CompleteOAuthLogin 校验 state 并用授权码换取 ID Token，返回映射到的本地用户
+// 映射顺序：已绑定的 sub → 邮箱一致的本地用户（需开启 link_by_email 且邮箱已验证）→ 自动创建（需开启 auto_create）
+func CompleteOAuthLogin(ctx context.Context, providerName, code, state string) (*models.User, error) {
+	oauthMu.Lock()
+	pending, ok := oauthStates[state]
+	delete(oauthStates, state)
+	oauthMu.Unlock()
+	if !ok || pending.provider != providerName || time.Now().After(pending.expiresAt) {
+		return nil, ErrOAuthStateInvalid
+	}
+
+	provider, err := getOAuthProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := provider.client.Exchange(ctx, code, pending.codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := provider.client.VerifyIDToken(ctx, tokens.IDToken, pending.nonce)
+	if err != nil {
+		return nil, fmt.Errorf("ID Token 校验失败: %w", err)
+	}
+
+	return provider.resolveUser(claims)
+}
+
+// resolveUser 将 ID Token 声明映射为本地用户
+func (p *OAuthProvider) resolveUser(claims *oidc.IDTokenClaims) (*models.User, error) {
+	identity, err := OAuthUsers.GetIdentity(p.Name, claims.Subject)
+	if err == nil {
+		if err := OAuthUsers.TouchIdentity(identity.ID, claims.Email); err != nil {
+			return nil, err
+		}
+		return OAuthUsers.GetUserByID(identity.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, ErrOAuthEmailMissing
+	}
+
+	now := time.Now()
+	identity = &models.UserIdentity{
+		Provider:    p.Name,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: &now,
+	}
+
+	existing, err := OAuthUsers.GetUserByEmail(claims.Email)
+	if err == nil {
+		if !p.linkByEmail || !claims.EmailVerified {
+			return nil, ErrOAuthEmailInUse
+		}
+		identity.UserID = existing.ID
+		if err := OAuthUsers.CreateIdentity(identity); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if !p.autoCreate {
+		return nil, ErrOAuthAccountNotLinked
+	}
+	// 邮箱可能属于已删除的账户
+	if taken, err := OAuthUsers.EmailTaken(claims.Email); err != nil {
+		return nil, err
+	} else if taken {
+		return nil, ErrOAuthEmailInUse
+	}
+
+	user, err := p.newUser(claims)
+	if err != nil {
+		return nil, err
+	}
+	if err := OAuthUsers.CreateUserWithIdentity(user, identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// usernameInvalidChars 用户名中不允许的字符
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// newUser 根据 ID Token 声明构造本地用户，用户名取自 preferred_username 或邮箱前缀，冲突时追加随机后缀（总长不超过20）
+// 本地密码设置为随机值，用户只能通过身份提供方登录（或自行重置密码）
+func (p *OAuthProvider) newUser(claims *oidc.IDTokenClaims) (*models.User, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	base = usernameInvalidChars.ReplaceAllString(base, "")
+	if len(base) > 13 {
+		base = base[:13]
+	}
+	if len(base) < 3 {
+		base = "user" + base
+	}
+
+	username := base
+	for i := 0; ; i++ {
+		taken, err := OAuthUsers.UsernameTaken(username)
+		if err != nil {
+			return nil, err
+		}
+		if !taken {
+			break
+		}
+		if i >= 5 {
+			return nil, errors.New("无法生成可用的用户名")
+		}
+		suffix, err := token.RandomString(4)
+		if err != nil {
+			return nil, err
+		}
+		username = base + "_" + strings.ToLower(usernameInvalidChars.ReplaceAllString(suffix, ""))
+	}
+
+	randomPassword, err := token.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	// 第三方登录账户不使用本地密码，保存一个随机密码，用户可通过重置密码设置
+	passwordHash, err := HashPassword(ClientHash(randomPassword))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Username:     username,
+		Email:        claims.Email,
+		PasswordHash: passwordHash,
+		UserType:     p.userType,
+		Status:       models.UserStatusActive,
+	}
+	if claims.EmailVerified {
+		user.EmailVerifiedAt = &now
+	}
+	return user, nil
+}
+
+// getOAuthProvider 根据名称获取身份提供方
+func getOAuthProvider(name string) (*OAuthProvider, error) {
+	oauthMu.Lock()
+	defer oauthMu.Unlock()
+
+	provider, ok := oauthProviders[name]
+	if !ok {
+		return nil, ErrOAuthProviderNotFound
+	}
+	return provider, nil
+}
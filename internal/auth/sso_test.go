@@ -0,0 +1,351 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-web/internal/models"
+	"go-web/pkg/oidc"
+	"go-web/pkg/oidc/mockidp"
+
+	"gorm.io/gorm"
+)
+
+const testProvider = "mock"
+
+// memoryOAuthUsers 测试用的内存用户存储
+type memoryOAuthUsers struct {
+	mu         sync.Mutex
+	users      map[uint]*models.User
+	identities []*models.UserIdentity
+	nextID     uint
+}
+
+func newMemoryOAuthUsers() *memoryOAuthUsers {
+	return &memoryOAuthUsers{users: make(map[uint]*models.User), nextID: 1}
+}
+
+func (s *memoryOAuthUsers) addUser(user *models.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user.ID = s.nextID
+	s.nextID++
+	s.users[user.ID] = user
+}
+
+func (s *memoryOAuthUsers) GetIdentity(provider, subject string) (*models.UserIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, identity := range s.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *memoryOAuthUsers) TouchIdentity(id uint, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, identity := range s.identities {
+		if identity.ID == id {
+			now := time.Now()
+			identity.Email = email
+			identity.LastLoginAt = &now
+		}
+	}
+	return nil
+}
+
+func (s *memoryOAuthUsers) CreateIdentity(identity *models.UserIdentity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	identity.ID = uint(len(s.identities) + 1)
+	s.identities = append(s.identities, identity)
+	return nil
+}
+
+func (s *memoryOAuthUsers) CreateUserWithIdentity(user *models.User, identity *models.UserIdentity) error {
+	s.addUser(user)
+	identity.UserID = user.ID
+	return s.CreateIdentity(identity)
+}
+
+func (s *memoryOAuthUsers) GetUserByID(id uint) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *memoryOAuthUsers) GetUserByEmail(email string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *memoryOAuthUsers) UsernameTaken(username string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if user.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memoryOAuthUsers) EmailTaken(email string) (bool, error) {
+	_, err := s.GetUserByEmail(email)
+	return err == nil, nil
+}
+
+// setupOAuth 启动本地 mock IdP 并注册为身份提供方，返回内存用户存储
+func setupOAuth(t *testing.T, idpConfig mockidp.Config, provider OAuthProvider) *memoryOAuthUsers {
+	t.Helper()
+
+	var idp *mockidp.Server
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idp.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	idpConfig.Issuer = srv.URL
+	idpConfig.ClientID = "dashboard"
+	idpConfig.ClientSecret = "dev-secret"
+	if idpConfig.Subject == "" {
+		idpConfig.Subject, idpConfig.Email = "mock-user-1", "sso.user@example.com"
+	}
+	var err error
+	if idp, err = mockidp.New(idpConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	provider.Name = testProvider
+	provider.client = oidc.NewProvider(oidc.Config{
+		Issuer:       srv.URL,
+		ClientID:     "dashboard",
+		ClientSecret: "dev-secret",
+		RedirectURL:  "http://app.test/oauth/callback",
+	})
+	if provider.userType == "" {
+		provider.userType = models.UserTypeApp
+	}
+
+	users := newMemoryOAuthUsers()
+	oauthMu.Lock()
+	oldProviders, oldUsers := oauthProviders, OAuthUsers
+	oauthProviders = map[string]*OAuthProvider{testProvider: &provider}
+	oauthStates = make(map[string]oauthPending)
+	OAuthUsers = users
+	oauthMu.Unlock()
+	t.Cleanup(func() {
+		oauthMu.Lock()
+		oauthProviders, OAuthUsers = oldProviders, oldUsers
+		oauthMu.Unlock()
+	})
+	return users
+}
+
+// authorize 发起登录并访问授权地址，返回身份提供方回跳的授权码和 state
+// loginHint 非空时以该邮箱登录
+func authorize(t *testing.T, loginHint string) (string, string) {
+	t.Helper()
+
+	authURL, state, err := BeginOAuthLogin(context.Background(), testProvider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loginHint != "" {
+		authURL += "&login_hint=" + url.QueryEscape(loginHint)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("授权端点返回 %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := location.Query().Get("state"); got != state {
+		t.Fatalf("回跳的 state = %q, 期望 %q", got, state)
+	}
+	return location.Query().Get("code"), state
+}
+
+// updatePending 修改尚未完成的登录请求
+func updatePending(state string, fn func(*oauthPending)) {
+	oauthMu.Lock()
+	defer oauthMu.Unlock()
+	pending := oauthStates[state]
+	fn(&pending)
+	oauthStates[state] = pending
+}
+
+func TestOAuthAutoCreateAndSubjectMapping(t *testing.T) {
+	users := setupOAuth(t, mockidp.Config{}, OAuthProvider{autoCreate: true})
+
+	code, state := authorize(t, "new.user@example.com")
+	user, err := CompleteOAuthLogin(context.Background(), testProvider, code, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID == 0 || user.Email != "new.user@example.com" || user.Username != "new.user" {
+		t.Fatalf("自动创建的用户 = %+v", user)
+	}
+	if user.EmailVerifiedAt == nil || user.UserType != models.UserTypeApp || user.Status != models.UserStatusActive {
+		t.Fatalf("自动创建的用户状态不正确: %+v", user)
+	}
+	if len(users.identities) != 1 || users.identities[0].UserID != user.ID {
+		t.Fatalf("未绑定外部账户: %+v", users.identities)
+	}
+
+	// 再次登录按 sub 映射到同一用户，不重复创建
+	code, state = authorize(t, "new.user@example.com")
+	again, err := CompleteOAuthLogin(context.Background(), testProvider, code, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != user.ID || len(users.users) != 1 || len(users.identities) != 1 {
+		t.Fatalf("按 sub 登录映射到 %d，用户数 %d", again.ID, len(users.users))
+	}
+	if users.identities[0].LastLoginAt == nil {
+		t.Fatal("未更新外部账户的最近登录时间")
+	}
+}
+
+func TestOAuthLinkByEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		linkByEmail bool
+		unverified  bool
+		wantErr     error
+	}{
+		{name: "邮箱已验证时绑定", linkByEmail: true},
+		{name: "未开启 link_by_email", linkByEmail: false, wantErr: ErrOAuthEmailInUse},
+		{name: "邮箱未验证", linkByEmail: true, unverified: true, wantErr: ErrOAuthEmailInUse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := setupOAuth(t, mockidp.Config{EmailUnverified: tt.unverified},
+				OAuthProvider{linkByEmail: tt.linkByEmail, autoCreate: true})
+			local := &models.User{Username: "alice", Email: "alice@example.com", Status: models.UserStatusActive}
+			users.addUser(local)
+
+			code, state := authorize(t, "alice@example.com")
+			user, err := CompleteOAuthLogin(context.Background(), testProvider, code, state)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, 期望 %v", err, tt.wantErr)
+				}
+				if len(users.identities) != 0 || len(users.users) != 1 {
+					t.Fatal("失败时不应绑定或创建用户")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user.ID != local.ID || len(users.identities) != 1 || users.identities[0].UserID != local.ID {
+				t.Fatalf("未绑定到已有用户: user=%+v identities=%+v", user, users.identities)
+			}
+		})
+	}
+}
+
+func TestOAuthNotLinkedWithoutAutoCreate(t *testing.T) {
+	users := setupOAuth(t, mockidp.Config{}, OAuthProvider{})
+
+	code, state := authorize(t, "")
+	if _, err := CompleteOAuthLogin(context.Background(), testProvider, code, state); !errors.Is(err, ErrOAuthAccountNotLinked) {
+		t.Fatalf("err = %v, 期望 ErrOAuthAccountNotLinked", err)
+	}
+	if len(users.users) != 0 {
+		t.Fatal("未开启 auto_create 时不应创建用户")
+	}
+}
+
+func TestOAuthPKCEMismatch(t *testing.T) {
+	users := setupOAuth(t, mockidp.Config{}, OAuthProvider{autoCreate: true})
+
+	code, state := authorize(t, "")
+	updatePending(state, func(p *oauthPending) { p.codeVerifier = "attacker-verifier" })
+
+	_, err := CompleteOAuthLogin(context.Background(), testProvider, code, state)
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Fatalf("err = %v, 期望令牌端点拒绝 invalid_grant", err)
+	}
+	if len(users.users) != 0 {
+		t.Fatal("PKCE 校验失败时不应创建用户")
+	}
+}
+
+func TestOAuthNonceMismatch(t *testing.T) {
+	setupOAuth(t, mockidp.Config{}, OAuthProvider{autoCreate: true})
+
+	code, state := authorize(t, "")
+	updatePending(state, func(p *oauthPending) { p.nonce = "other-nonce" })
+
+	if _, err := CompleteOAuthLogin(context.Background(), testProvider, code, state); !errors.Is(err, oidc.ErrNonceMismatch) {
+		t.Fatalf("err = %v, 期望 ErrNonceMismatch", err)
+	}
+}
+
+func TestOAuthStateExpiredOrReused(t *testing.T) {
+	setupOAuth(t, mockidp.Config{}, OAuthProvider{autoCreate: true})
+	ctx := context.Background()
+
+	code, state := authorize(t, "")
+	updatePending(state, func(p *oauthPending) { p.expiresAt = time.Now().Add(-time.Second) })
+	if _, err := CompleteOAuthLogin(ctx, testProvider, code, state); !errors.Is(err, ErrOAuthStateInvalid) {
+		t.Fatalf("过期 state: err = %v", err)
+	}
+
+	code, state = authorize(t, "")
+	if _, err := CompleteOAuthLogin(ctx, testProvider, code, state); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CompleteOAuthLogin(ctx, testProvider, code, state); !errors.Is(err, ErrOAuthStateInvalid) {
+		t.Fatalf("重复使用 state: err = %v", err)
+	}
+
+	if _, err := CompleteOAuthLogin(ctx, testProvider, code, "unknown-state"); !errors.Is(err, ErrOAuthStateInvalid) {
+		t.Fatalf("未知 state: err = %v", err)
+	}
+}
+
+func TestOAuthStateBinding(t *testing.T) {
+	binding := OAuthStateBinding("state-1")
+	if !OAuthStateBound("state-1", binding) {
+		t.Fatal("同一浏览器的绑定值应通过")
+	}
+	if OAuthStateBound("state-2", binding) {
+		t.Fatal("其他登录请求的绑定值不应通过")
+	}
+	if OAuthStateBound("state-1", "") || OAuthStateBound("", "") {
+		t.Fatal("缺少绑定 Cookie 时不应通过")
+	}
+}
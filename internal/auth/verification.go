@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"go-web/internal/dao"
+	"go-web/pkg/logger"
+	"go-web/pkg/token"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrVerificationTokenInvalid = errors.New("验证链接无效")
+	ErrVerificationTokenExpired = errors.New("验证链接已过期")
+)
+
+// EmailVerificationEnabled 是否开启注册邮箱验证，开启后新用户需验证邮箱才能激活
+func EmailVerificationEnabled() bool {
+	return viper.GetBool("email_verification.enabled")
+}
+
+// UnverifiedAccountTTL 未验证账户的保留时长，超过后账户被删除，默认72小时
+func UnverifiedAccountTTL() time.Duration {
+	return durationOr("email_verification.unverified_expire", 72*time.Hour)
+}
+
+// VerifyEmail 校验邮箱验证令牌并确认邮箱，等待验证的新注册账户同时被激活
+// 令牌中的邮箱与账户当前邮箱不一致、或邮箱已验证时返回 ErrVerificationTokenInvalid
+func VerifyEmail(tokenString string) (uint, error) {
+	claims, err := token.ParseTokenOfType(tokenString, token.TypeEmailVerify)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return 0, ErrVerificationTokenExpired
+		}
+		return 0, ErrVerificationTokenInvalid
+	}
+
+	verified, err := dao.VerifyUserEmail(claims.UserID, claims.Email)
+	if err != nil {
+		return 0, err
+	}
+	if !verified {
+		return 0, ErrVerificationTokenInvalid
+	}
+	return claims.UserID, nil
+}
+
+// CleanupUnverifiedUsers 删除超过保留时长仍未验证邮箱的账户
+func CleanupUnverifiedUsers() (int64, error) {
+	return dao.DeleteUnverifiedUsers(time.Now().Add(-UnverifiedAccountTTL()))
+}
+
+// StartUnverifiedCleanup 启动后台任务定期清理未验证账户，返回停止函数
+func StartUnverifiedCleanup(interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				deleted, err := CleanupUnverifiedUsers()
+				if err != nil {
+					logger.Errorw("清理未验证账户失败", "error", err)
+				} else if deleted > 0 {
+					logger.Infow("已清理过期的未验证账户", "count", deleted)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -0,0 +1,285 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-web/internal/auth"
+	"go-web/internal/dao"
+	"go-web/internal/middleware"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// UpdateUserStatusHandler 启用/停用用户（管理员）
+func UpdateUserStatusHandler(c *gin.Context) {
+	var req models.UpdateUserStatusRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("修改用户状态请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	target, ok := loadTargetUser(c)
+	if !ok {
+		return
+	}
+
+	action := models.AuditActionUserActivate
+	if req.Status == string(models.UserStatusInactive) {
+		action = models.AuditActionUserDeactivate
+		if rejectSelfOperation(c, target, action) {
+			return
+		}
+	}
+
+	// 管理员手动激活视为邮箱已确认，避免账户被未验证清理任务删除
+	updates := map[string]interface{}{"status": req.Status}
+	if req.Status == string(models.UserStatusActive) && target.EmailVerificationPending {
+		updates["email_verification_pending"] = false
+	}
+
+	oldStatus := target.Status
+	if err := dao.GetDB().Model(target).Updates(updates).Error; err != nil {
+		logger.Errorw("修改用户状态失败", "error", err, "user_id", target.ID)
+		recordAudit(c, action, "user", userTargetID(target), models.AuditResultFailure, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "修改用户状态失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 停用账户后立即吊销其所有会话
+	if target.Status == models.UserStatusInactive {
+		if err := auth.RevokeUserSessions(target.ID, models.SessionRevokeAdminDeactivate, ""); err != nil {
+			logger.Errorw("吊销用户会话失败", "error", err, "user_id", target.ID)
+		}
+	}
+
+	recordAudit(c, action, "user", userTargetID(target), models.AuditResultSuccess, gin.H{
+		"from": oldStatus,
+		"to":   target.Status,
+	})
+	logger.Infow("用户状态已修改", "user_id", target.ID, "status", target.Status)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "修改成功",
+		Data:    target,
+	})
+}
+
+// UpdateUserTypeHandler 修改用户类型（管理员）
+func UpdateUserTypeHandler(c *gin.Context) {
+	var req models.UpdateUserTypeRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("修改用户类型请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	target, ok := loadTargetUser(c)
+	if !ok {
+		return
+	}
+	if rejectSelfOperation(c, target, models.AuditActionUserTypeChange) {
+		return
+	}
+
+	oldType := target.UserType
+	if oldType == models.UserType(req.UserType) {
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Code:    http.StatusOK,
+			Message: "修改成功",
+			Data:    target,
+		})
+		return
+	}
+
+	mfaEnabled, err := auth.MFAEnabled(target.ID)
+	if err != nil {
+		logger.Errorw("查询二次验证配置失败", "error", err, "user_id", target.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "修改用户类型失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 类型变更时同时撤销二次验证，避免残留的配置脱离新类型的管理规则
+	if err := dao.ChangeUserType(target.ID, models.UserType(req.UserType)); err != nil {
+		logger.Errorw("修改用户类型失败", "error", err, "user_id", target.ID)
+		recordAudit(c, models.AuditActionUserTypeChange, "user", userTargetID(target), models.AuditResultFailure, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "修改用户类型失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	target.UserType = models.UserType(req.UserType)
+
+	recordAudit(c, models.AuditActionUserTypeChange, "user", userTargetID(target), models.AuditResultSuccess, gin.H{
+		"from":        oldType,
+		"to":          target.UserType,
+		"mfa_revoked": mfaEnabled,
+	})
+	logger.Infow("用户类型已修改", "user_id", target.ID, "user_type", target.UserType)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "修改成功",
+		Data:    target,
+	})
+}
+
+// ForcePasswordResetHandler 强制用户重置密码（管理员）
+// 吊销用户全部会话，要求其通过重置邮件设置新密码后才能再次登录
+func ForcePasswordResetHandler(c *gin.Context) {
+	target, ok := loadTargetUser(c)
+	if !ok {
+		return
+	}
+
+	if err := dao.GetDB().Model(target).Update("password_reset_required", true).Error; err != nil {
+		logger.Errorw("设置强制重置密码失败", "error", err, "user_id", target.ID)
+		recordAudit(c, models.AuditActionUserForceReset, "user", userTargetID(target), models.AuditResultFailure, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "操作失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := auth.RevokeUserSessions(target.ID, models.SessionRevokeAdminForceReset, ""); err != nil {
+		logger.Errorw("吊销用户会话失败", "error", err, "user_id", target.ID)
+	}
+
+	emailSent := true
+	if err := issuePasswordReset(c, target); err != nil {
+		emailSent = false
+		logger.Errorw("发送密码重置邮件失败", "error", err, "user_id", target.ID)
+	}
+
+	recordAudit(c, models.AuditActionUserForceReset, "user", userTargetID(target), models.AuditResultSuccess, gin.H{
+		"email_sent": emailSent,
+	})
+	logger.Infow("已强制用户重置密码", "user_id", target.ID)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "已要求用户重置密码",
+		Data:    gin.H{"email_sent": emailSent},
+	})
+}
+
+// DeleteUserHandler 软删除用户（管理员）
+func DeleteUserHandler(c *gin.Context) {
+	target, ok := loadTargetUser(c)
+	if !ok {
+		return
+	}
+	if rejectSelfOperation(c, target, models.AuditActionUserDelete) {
+		return
+	}
+
+	if err := dao.GetDB().Delete(target).Error; err != nil {
+		logger.Errorw("删除用户失败", "error", err, "user_id", target.ID)
+		recordAudit(c, models.AuditActionUserDelete, "user", userTargetID(target), models.AuditResultFailure, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "删除用户失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := auth.RevokeUserSessions(target.ID, models.SessionRevokeAdminDelete, ""); err != nil {
+		logger.Errorw("吊销用户会话失败", "error", err, "user_id", target.ID)
+	}
+
+	recordAudit(c, models.AuditActionUserDelete, "user", userTargetID(target), models.AuditResultSuccess, gin.H{
+		"username": target.Username,
+		"email":    target.Email,
+	})
+	logger.Infow("用户已删除", "user_id", target.ID)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "删除成功",
+		Data:    gin.H{},
+	})
+}
+
+// loadTargetUser 根据路径参数 :id 加载被操作的用户，失败时直接写入响应
+func loadTargetUser(c *gin.Context) (*models.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "用户ID无效",
+		})
+		return nil, false
+	}
+
+	user, err := dao.GetUserByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "用户不存在",
+			})
+			return nil, false
+		}
+		logger.Errorw("查询用户失败", "error", err, "user_id", id)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "查询用户失败",
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+
+	return user, true
+}
+
+// rejectSelfOperation 禁止管理员对自己执行停用、降级、删除等操作，被拒绝时返回 true
+func rejectSelfOperation(c *gin.Context, target *models.User, action string) bool {
+	current, _ := middleware.CurrentUser(c)
+	if current == nil || current.ID != target.ID {
+		return false
+	}
+
+	recordAudit(c, action, "user", userTargetID(target), models.AuditResultFailure, gin.H{"error": "self_operation"})
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: "不能对自己的账户执行此操作",
+	})
+	return true
+}
+
+// userTargetID 审计记录中的用户ID
+func userTargetID(user *models.User) string {
+	return strconv.FormatUint(uint64(user.ID), 10)
+}
@@ -0,0 +1,312 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+	"go-web/pkg/stats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// analyticsSpec 分析接口的查询规格
+type analyticsSpec struct {
+	sortFields      map[string]string // 允许排序的字段，参数字段 -> 数据库列
+	nameColumn      string            // 名称列，用作次要排序保证结果稳定
+	defaultSort     string
+	fixedSort       bool // 排行类接口固定按数值排序，不接受 sort 参数
+	defaultPageSize int  // 未指定分页时的每页数量，0 表示返回全部
+	failMessage     string
+	values          func(dao.AnalyticsFilter) ([]dao.AnalyticsValue, error) // 读取全部名称和数值，用于计算占比和汇总
+}
+
+// analyticsMaxPageSize 每页最大数量，超出时按最大值处理，与旧接口的行为保持一致
+const analyticsMaxPageSize = 100
+
+// ?with= 可选的附加字段
+const (
+	withShare      = "share"
+	withCumulative = "cumulative"
+	withRank       = "rank"
+	withSummary    = "summary"
+)
+
+// 各分析数据表允许排序的字段
+var (
+	brandSortFields = map[string]string{
+		"brand_name":  "brand_name",
+		"total_sales": "total_sales",
+	}
+	citySortFields = map[string]string{
+		"city":  "city",
+		"sales": "sales",
+	}
+	carLevelSortFields = map[string]string{
+		"car_level": "car_level",
+		"car_count": "car_count",
+	}
+	energySortFields = map[string]string{
+		"energy_name": "energy_type",
+		"count":       "car_count",
+	}
+)
+
+// analyticsPage 解析后的分析查询条件
+type analyticsPage struct {
+	filter    dao.AnalyticsFilter
+	orderBy   string
+	page      int
+	pageSize  int             // 0 表示不分页
+	with      map[string]bool // 请求的附加字段，非空时同时返回汇总统计
+	others    bool            // 追加“其他”行
+	threshold float64         // 占比低于该百分比的数据项并入“其他”，0 表示不按占比合并
+}
+
+// parseAnalyticsQuery 解析分析接口通用查询参数：sort、page/page_size、min/max、contains
+func parseAnalyticsQuery(c *gin.Context, spec analyticsSpec) (*analyticsPage, error) {
+	var req models.AnalyticsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+
+	if spec.fixedSort && req.Sort != "" {
+		return nil, errors.New("该接口按固定顺序排行，不支持 sort 参数")
+	}
+	// “其他”行合并未返回的全部数据项，翻页时会与前面页的数据重复计算
+	if (req.Others || req.Threshold > 0) && req.Page > 1 {
+		return nil, errors.New("others 和 threshold 仅适用于第一页")
+	}
+	if req.Min != nil && req.Max != nil && *req.Min > *req.Max {
+		return nil, errors.New("min 不能大于 max")
+	}
+
+	column, desc, err := parseSort(req.Sort, spec.sortFields, spec.defaultSort)
+	if err != nil {
+		return nil, err
+	}
+	// 追加名称列排序，保证数值相同时顺序稳定
+	orderBy := orderClause(column, desc)
+	if column != spec.nameColumn {
+		orderBy += ", " + orderClause(spec.nameColumn, false)
+	}
+
+	with, err := parseWith(req.With)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &analyticsPage{
+		filter: dao.AnalyticsFilter{
+			Min:      req.Min,
+			Max:      req.Max,
+			Contains: strings.TrimSpace(req.Contains),
+		},
+		orderBy:   orderBy,
+		page:      req.Page,
+		pageSize:  req.PageSize,
+		with:      with,
+		others:    req.Others || req.Threshold > 0,
+		threshold: req.Threshold,
+	}
+	if p.pageSize == 0 {
+		p.pageSize = req.Limit
+	}
+	if p.pageSize == 0 && p.page > 0 {
+		p.pageSize = 20
+	}
+	if p.pageSize == 0 {
+		p.pageSize = spec.defaultPageSize
+	}
+	if p.pageSize > analyticsMaxPageSize {
+		p.pageSize = analyticsMaxPageSize
+	}
+	if p.page == 0 {
+		p.page = 1
+	}
+	return p, nil
+}
+
+// parseWith 解析逗号分隔的附加字段列表
+func parseWith(raw string) (map[string]bool, error) {
+	with := make(map[string]bool)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		switch item {
+		case "":
+		case withShare, withCumulative, withRank, withSummary:
+			with[item] = true
+		default:
+			return nil, fmt.Errorf("不支持的 with 选项: %s", item)
+		}
+	}
+	return with, nil
+}
+
+// offset 分页偏移量
+func (p *analyticsPage) offset() int {
+	return (p.page - 1) * p.pageSize
+}
+
+// pagination 构造分页信息，不分页时视为包含全部数据的单页
+func (p *analyticsPage) pagination(total int64, returned int) models.PaginationResponse {
+	if p.pageSize == 0 {
+		pages := 0
+		if total > 0 {
+			pages = 1
+		}
+		return models.PaginationResponse{Total: int(total), Page: 1, Limit: returned, Pages: pages}
+	}
+	return models.PaginationResponse{
+		Total: int(total),
+		Page:  p.page,
+		Limit: p.pageSize,
+		Pages: int((total + int64(p.pageSize) - 1) / int64(p.pageSize)),
+	}
+}
+
+// respondAnalytics 解析查询参数、查询数据并返回分页响应
+func respondAnalytics[T any, PT interface {
+	*T
+	models.AnalyticsItem
+}](c *gin.Context, spec analyticsSpec, list func(dao.AnalyticsFilter, string, int, int) ([]T, int64, error)) {
+	p, err := parseAnalyticsQuery(c, spec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondError := func(err error) {
+		logger.Errorw(spec.failMessage, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: spec.failMessage,
+			Error:   err.Error(),
+		})
+	}
+
+	// 占比、排名、汇总和“其他”行都基于满足过滤条件的全部数据，而不是当前页
+	var values []dao.AnalyticsValue
+	var summary stats.Summary
+	if len(p.with) > 0 || p.others {
+		values, err = spec.values(p.filter)
+		if err != nil {
+			respondError(err)
+			return
+		}
+		summary = summarizeValues(values)
+	}
+
+	// 按占比阈值合并时，列表只保留达到阈值的数据项
+	listFilter := p.filter
+	if p.threshold > 0 {
+		floor := summary.Total * p.threshold / 100
+		if listFilter.Min == nil || *listFilter.Min < floor {
+			listFilter.Min = &floor
+		}
+	}
+
+	rows, total, err := list(listFilter, p.orderBy, p.offset(), p.pageSize)
+	if err != nil {
+		respondError(err)
+		return
+	}
+
+	if len(p.with) > 0 {
+		metrics := computeMetrics(values, summary.Total, p.with)
+		for i := range rows {
+			item := PT(&rows[i])
+			if m, ok := metrics[item.ItemName()]; ok {
+				item.SetMetrics(m)
+			}
+		}
+	}
+
+	// “其他”行合并所有满足过滤条件但未在本次结果中返回的数据项，使饼图总量完整
+	if p.others {
+		var returned float64
+		for i := range rows {
+			returned += PT(&rows[i]).ItemValue()
+		}
+		if grouped := len(values) - len(rows); grouped > 0 {
+			var others T
+			item := PT(&others)
+			item.SetItem(othersLabel(c), summary.Total-returned)
+			m := models.AnalyticsMetrics{Others: true, GroupedCount: grouped}
+			if p.with[withShare] {
+				share := stats.Round(stats.Percent(item.ItemValue(), summary.Total), 4)
+				m.Share = &share
+			}
+			item.SetMetrics(m)
+			rows = append(rows, others)
+		}
+	}
+
+	response := models.AnalyticsResponse{
+		Code:       http.StatusOK,
+		Message:    "获取成功",
+		Data:       rows,
+		Pagination: p.pagination(total, len(rows)),
+	}
+	if len(p.with) > 0 {
+		response.Summary = &summary
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// othersLabel 根据 Accept-Language 选择“其他”行的名称
+func othersLabel(c *gin.Context) string {
+	if strings.HasPrefix(strings.ToLower(c.GetHeader("Accept-Language")), "en") {
+		return models.OthersLabelEn
+	}
+	return models.OthersLabel
+}
+
+// summarizeValues 计算全部数据项数值的汇总统计
+func summarizeValues(values []dao.AnalyticsValue) stats.Summary {
+	raw := make([]float64, len(values))
+	for i, v := range values {
+		raw[i] = v.Value
+	}
+	return stats.Summarize(raw)
+}
+
+// computeMetrics 根据按数值降序排列的全部数据计算每项的占比、累计占比和排名
+func computeMetrics(values []dao.AnalyticsValue, total float64, with map[string]bool) map[string]models.AnalyticsMetrics {
+	metrics := make(map[string]models.AnalyticsMetrics, len(values))
+	var cumulative float64
+	rank := 0
+	for i, v := range values {
+		cumulative += v.Value
+		// 数值相同的项排名相同（1224 排名法）
+		if i == 0 || v.Value != values[i-1].Value {
+			rank = i + 1
+		}
+
+		var m models.AnalyticsMetrics
+		if with[withShare] {
+			share := stats.Round(stats.Percent(v.Value, total), 4)
+			m.Share = &share
+		}
+		if with[withCumulative] {
+			cumulativeShare := stats.Round(stats.Percent(cumulative, total), 4)
+			m.CumulativeShare = &cumulativeShare
+		}
+		if with[withRank] {
+			r := rank
+			m.Rank = &r
+		}
+		if _, exists := metrics[v.Name]; !exists {
+			metrics[v.Name] = m
+		}
+	}
+	return metrics
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseAnalyticsQueryPageSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spec := analyticsSpec{
+		sortFields:      citySortFields,
+		nameColumn:      "city",
+		defaultSort:     "sales:desc",
+		fixedSort:       true,
+		defaultPageSize: 10,
+	}
+
+	tests := []struct {
+		query    string
+		pageSize int
+		wantErr  bool
+	}{
+		{query: "", pageSize: 10},
+		{query: "limit=0", pageSize: 10},
+		{query: "limit=30", pageSize: 30},
+		{query: "limit=200", pageSize: analyticsMaxPageSize},
+		{query: "page_size=500", pageSize: analyticsMaxPageSize},
+		{query: "limit=-1", wantErr: true},
+		{query: "limit=abc", wantErr: true},
+		{query: "page_size=-5", wantErr: true},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/city/top-sales?"+tt.query, nil)
+
+		p, err := parseAnalyticsQuery(c, spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: 期望返回错误", tt.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: 意外错误 %v", tt.query, err)
+			continue
+		}
+		if p.pageSize != tt.pageSize {
+			t.Errorf("%q: pageSize = %d, 期望 %d", tt.query, p.pageSize, tt.pageSize)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-web/internal/auth"
+	"go-web/internal/dao"
+	"go-web/internal/middleware"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListAPIKeysHandler 获取当前用户的 API 密钥列表（不含密钥明文）
+func ListAPIKeysHandler(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+
+	keys, err := dao.ListUserAPIKeys(user.ID)
+	if err != nil {
+		logger.Errorw("查询 API 密钥失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "获取 API 密钥失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    keys,
+	})
+}
+
+// CreateAPIKeyHandler 创建 API 密钥，密钥明文只在本次响应中返回
+func CreateAPIKeyHandler(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("创建 API 密钥请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+
+	key, plain, err := auth.CreateAPIKey(user, req.Name, req.Scopes, req.ExpiresInDays)
+	if err != nil {
+		if errors.Is(err, auth.ErrAPIKeyScopeInvalid) || errors.Is(err, auth.ErrAPIKeyExpireTooFar) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "请求参数错误",
+				Error:   err.Error(),
+			})
+			return
+		}
+		logger.Errorw("创建 API 密钥失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "创建 API 密钥失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	recordAudit(c, models.AuditActionAPIKeyCreate, "api_key", strconv.FormatUint(uint64(key.ID), 10), models.AuditResultSuccess, gin.H{
+		"name":   key.Name,
+		"scopes": key.ScopeList,
+	})
+	logger.Infow("API 密钥已创建", "user_id", user.ID, "key_id", key.ID)
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "创建成功，请妥善保存密钥，关闭后将无法再次查看",
+		Data: gin.H{
+			"key":     plain,
+			"api_key": key,
+		},
+	})
+}
+
+// RevokeAPIKeyHandler 吊销当前用户的 API 密钥
+func RevokeAPIKeyHandler(c *gin.Context) {
+	keyID, err := strconv.ParseUint(c.Param("keyId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   "无效的密钥ID",
+		})
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+
+	revoked, err := dao.RevokeAPIKey(user.ID, uint(keyID))
+	if err != nil {
+		logger.Errorw("吊销 API 密钥失败", "error", err, "user_id", user.ID, "key_id", keyID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "吊销 API 密钥失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "API 密钥不存在或已吊销",
+		})
+		return
+	}
+
+	recordAudit(c, models.AuditActionAPIKeyRevoke, "api_key", c.Param("keyId"), models.AuditResultSuccess, nil)
+	logger.Infow("API 密钥已吊销", "user_id", user.ID, "key_id", keyID)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "吊销成功",
+	})
+}
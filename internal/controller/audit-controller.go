@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-web/internal/dao"
+	"go-web/internal/middleware"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// auditExportBatchSize 导出审计日志时每批读取的行数
+const auditExportBatchSize = 500
+
+// auditCSVHeader 审计日志 CSV 表头
+var auditCSVHeader = []string{
+	"id", "created_at", "actor_id", "actor_username", "action", "target_type",
+	"target_id", "result", "ip_address", "user_agent", "details",
+}
+
+// auditExportMaxRows 单次导出的最大行数，默认10万
+func auditExportMaxRows() int {
+	rows := viper.GetInt("audit.export_max_rows")
+	if rows <= 0 {
+		rows = 100000
+	}
+	return rows
+}
+
+// ListAuditEventsHandler 查询审计日志（仅管理员），format=csv 时导出为 CSV 文件
+func ListAuditEventsHandler(c *gin.Context) {
+	var req models.AuditEventQuery
+
+	// 绑定并验证查询参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Errorw("审计日志查询参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	filter := dao.AuditEventFilter{
+		ActorID:    req.ActorID,
+		Actor:      strings.TrimSpace(req.Actor),
+		Action:     strings.TrimSpace(req.Action),
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Result:     req.Result,
+		IP:         req.IP,
+	}
+	// 日期格式已由 binding 校验，截止日期包含当天
+	if req.From != "" {
+		from, _ := time.ParseInLocation(time.DateOnly, req.From, time.Local)
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, _ := time.ParseInLocation(time.DateOnly, req.To, time.Local)
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	if req.Format == "csv" {
+		exportAuditEvents(c, filter)
+		return
+	}
+
+	events, total, err := dao.ListAuditEvents(filter, (req.Page-1)*req.Limit, req.Limit)
+	if err != nil {
+		logger.Errorw("获取审计日志失败", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "获取审计日志失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PagedResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    events,
+		Pagination: models.PaginationResponse{
+			Total: int(total),
+			Page:  req.Page,
+			Limit: req.Limit,
+			Pages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+		},
+	})
+}
+
+// exportAuditEvents 分批读取并以 CSV 流式输出审计日志
+// 响应头发出后无法再返回错误响应，中途失败只记录日志并截断输出
+func exportAuditEvents(c *gin.Context, filter dao.AuditEventFilter) {
+	filename := "audit-events-" + time.Now().Format("20060102-150405") + ".csv"
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	// 写入 UTF-8 BOM，便于 Excel 正确识别中文
+	c.Writer.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(c.Writer)
+	w.Write(auditCSVHeader)
+
+	rows := 0
+	err := dao.EachAuditEvent(filter, auditExportBatchSize, auditExportMaxRows(), func(events []models.AuditEvent) error {
+		for _, event := range events {
+			if err := w.Write(auditCSVRecord(&event)); err != nil {
+				return err
+			}
+		}
+		rows += len(events)
+		w.Flush()
+		c.Writer.Flush()
+		return w.Error()
+	})
+	w.Flush()
+	if err != nil {
+		logger.Errorw("导出审计日志失败", "error", err, "rows", rows)
+		return
+	}
+
+	admin, _ := middleware.CurrentUser(c)
+	logger.Infow("管理员导出审计日志", "admin_id", admin.ID, "rows", rows)
+}
+
+// auditCSVRecord 将审计事件转换为 CSV 行
+func auditCSVRecord(event *models.AuditEvent) []string {
+	actorID := ""
+	if event.ActorID != nil {
+		actorID = strconv.FormatUint(uint64(*event.ActorID), 10)
+	}
+	return []string{
+		strconv.FormatUint(uint64(event.ID), 10),
+		event.CreatedAt.Format(time.RFC3339),
+		actorID,
+		event.ActorUsername,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.Result,
+		event.IPAddress,
+		event.UserAgent,
+		string(event.Details),
+	}
+}
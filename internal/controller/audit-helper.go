@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"go-web/internal/dao"
+	"go-web/internal/middleware"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordAudit 记录审计事件，操作者取自当前认证用户
+// 审计写入失败只记录日志，不影响业务响应
+func recordAudit(c *gin.Context, action, targetType, targetID, result string, details gin.H) {
+	actor, _ := middleware.CurrentUser(c)
+	writeAudit(c, actor, action, targetType, targetID, result, details)
+}
+
+// recordUserAudit 记录以用户自身为对象的认证事件（登录、登出、改密码等），操作者即该用户
+// 未认证的请求（如登录失败）user 可以为 nil，此时应在 details 中记录尝试的登录标识
+func recordUserAudit(c *gin.Context, user *models.User, action, result string, details gin.H) {
+	targetID := ""
+	if user != nil {
+		targetID = strconv.FormatUint(uint64(user.ID), 10)
+	}
+	writeAudit(c, user, action, "user", targetID, result, details)
+}
+
+// writeAudit 写入审计事件
+func writeAudit(c *gin.Context, actor *models.User, action, targetType, targetID, result string, details gin.H) {
+	event := models.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IPAddress:  c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		Result:     result,
+	}
+
+	if actor != nil {
+		event.ActorID = &actor.ID
+		event.ActorUsername = actor.Username
+	}
+
+	if details != nil {
+		raw, err := json.Marshal(details)
+		if err != nil {
+			logger.Errorw("序列化审计详情失败", "error", err, "action", action)
+		} else {
+			event.Details = raw
+		}
+	}
+
+	if err := dao.CreateAuditEvent(&event); err != nil {
+		logger.Errorw("写入审计事件失败", "error", err, "action", action, "target_id", targetID)
+	}
+}
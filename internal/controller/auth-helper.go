@@ -0,0 +1,384 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go-web/internal/auth"
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+	"go-web/pkg/mailer"
+	"go-web/pkg/token"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// 刷新令牌失败原因（写入 ErrorResponse.Error，供前端识别）
+const (
+	reasonRefreshTokenInvalid   = "refresh_token_invalid"
+	reasonRefreshTokenExpired   = "refresh_token_expired"
+	reasonRefreshTokenRevoked   = "refresh_token_revoked"
+	reasonRefreshTokenReused    = "refresh_token_reused"
+	reasonResetTokenInvalid     = "reset_token_invalid"
+	reasonPasswordResetRequired = "password_reset_required"
+	reasonTooManyAttempts       = "too_many_attempts"
+	reasonEmailNotVerified      = "email_not_verified"
+	reasonVerifyTokenInvalid    = "verify_token_invalid"
+	reasonVerifyTokenExpired    = "verify_token_expired"
+	reasonOAuthStateInvalid     = "oauth_state_invalid"
+	reasonOAuthLoginFailed      = "oauth_login_failed"
+	reasonOAuthNotLinked        = "oauth_account_not_linked"
+	reasonOAuthEmailInUse       = "oauth_email_in_use"
+	reasonPasswordFormat        = "password_format_invalid"
+	reasonPasswordTooWeak       = "password_too_weak"
+	reasonPasswordBreached      = "password_breached"
+	reasonPasswordReused        = "password_reused"
+	reasonPasswordPrehashed     = "password_prehashed"
+)
+
+// newUserSession 为用户签发访问令牌和刷新令牌，并构造对应的会话记录（未落库）
+// familyID 为空表示新登录，会开启新的会话族；刷新轮换时沿用原会话族
+func newUserSession(c *gin.Context, userID uint, familyID string) (*models.UserSession, *models.TokenResponse, error) {
+	if familyID == "" {
+		id, err := token.RandomString(16)
+		if err != nil {
+			return nil, nil, err
+		}
+		familyID = id
+	}
+
+	accessToken, err := token.GenerateAccessToken(userID, familyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := token.GenerateRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	session := &models.UserSession{
+		UserID:           userID,
+		FamilyID:         familyID,
+		Token:            token.HashToken(accessToken),
+		RefreshToken:     token.HashToken(refreshToken),
+		ExpiresAt:        now.Add(token.AccessTokenTTL()),
+		RefreshExpiresAt: now.Add(token.RefreshTokenTTL()),
+		UserAgent:        c.Request.UserAgent(),
+		IPAddress:        c.ClientIP(),
+	}
+
+	tokens := &models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(token.AccessTokenTTL().Seconds()),
+	}
+
+	return session, tokens, nil
+}
+
+// upgradePasswordHash 使用当前配置的 bcrypt 代价重新加密用户密码，失败只记录日志
+func upgradePasswordHash(user *models.User, clientHash string) {
+	hashed, err := auth.HashPassword(clientHash)
+	if err != nil {
+		logger.Errorw("密码重新加密失败", "error", err, "user_id", user.ID)
+		return
+	}
+	if err := dao.UpgradePasswordHash(user.ID, user.PasswordHash, hashed); err != nil {
+		logger.Errorw("更新密码哈希失败", "error", err, "user_id", user.ID)
+		return
+	}
+	user.PasswordHash = hashed
+	logger.Infow("已按新的 bcrypt 代价升级密码哈希", "user_id", user.ID, "cost", auth.BcryptCost())
+}
+
+// passwordPrehashed 判断请求中的密码是否为客户端 SHA-256 哈希，未指定 password_format 时按旧版客户端处理
+func passwordPrehashed(format string) bool {
+	return format != models.PasswordFormatPlain
+}
+
+// preparePassword 按密码策略校验新密码并加密，未通过时直接写入错误响应并返回 false
+// userID 为0表示新注册用户，format 为请求中的 password_format
+func preparePassword(c *gin.Context, userID uint, password, format string) (string, bool) {
+	hashed, err := auth.PreparePassword(userID, password, passwordPrehashed(format))
+	if err == nil {
+		return hashed, true
+	}
+
+	policy := auth.CurrentPasswordPolicy()
+	var message, reason string
+	switch {
+	case errors.Is(err, auth.ErrPasswordTooShort), errors.Is(err, auth.ErrPasswordTooLong),
+		errors.Is(err, auth.ErrPasswordCharClasses):
+		message, reason = policy.Requirement(), reasonPasswordTooWeak
+	case errors.Is(err, auth.ErrPasswordBreached):
+		message, reason = "该密码已出现在泄露密码列表中，请更换密码", reasonPasswordBreached
+	case errors.Is(err, auth.ErrPasswordReused):
+		message = fmt.Sprintf("不能使用最近 %d 次用过的密码", policy.HistorySize)
+		reason = reasonPasswordReused
+	case errors.Is(err, auth.ErrPasswordPrehashed):
+		message, reason = "设置密码时请提交明文密码（password_format 为 plain）", reasonPasswordPrehashed
+	case errors.Is(err, auth.ErrPasswordFormat):
+		message, reason = "密码格式无效", reasonPasswordFormat
+	default:
+		logger.Errorw("密码加密失败", "error", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "密码加密失败",
+			Error:   err.Error(),
+		})
+		return "", false
+	}
+
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: message,
+		Error:   reason,
+	})
+	return "", false
+}
+
+// passwordResetTTL 密码重置令牌有效期，默认30分钟
+func passwordResetTTL() time.Duration {
+	ttl := viper.GetDuration("password_reset.token_expire")
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	return ttl
+}
+
+// issuePasswordReset 为用户生成一次性密码重置令牌并发送重置邮件
+func issuePasswordReset(c *gin.Context, user *models.User) error {
+	rawToken, err := token.RandomString(32)
+	if err != nil {
+		return err
+	}
+
+	resetToken := models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: token.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(passwordResetTTL()),
+		RequestIP: c.ClientIP(),
+	}
+	if err := dao.CreatePasswordResetToken(&resetToken); err != nil {
+		return err
+	}
+
+	sendPasswordResetEmail(user, rawToken)
+	return nil
+}
+
+// sendPasswordResetEmail 异步发送密码重置邮件，避免响应耗时暴露邮箱是否存在
+func sendPasswordResetEmail(user *models.User, rawToken string) {
+	resetURL := viper.GetString("password_reset.reset_url")
+	if resetURL == "" {
+		resetURL = "http://localhost:5173/reset-password?token=%s"
+	}
+
+	msg := mailer.Message{
+		To:      []string{user.Email},
+		Subject: "重置您的密码",
+		Body: fmt.Sprintf("%s，您好：\n\n我们收到了重置您账户密码的请求，请在 %d 分钟内点击以下链接完成重置：\n\n%s\n\n如果这不是您本人的操作，请忽略此邮件，您的密码不会被修改。",
+			user.Username, int(passwordResetTTL().Minutes()), fmt.Sprintf(resetURL, rawToken)),
+	}
+
+	go func() {
+		if err := mailer.Send(msg); err != nil {
+			logger.Errorw("发送密码重置邮件失败", "error", err, "user_id", user.ID)
+		}
+	}()
+}
+
+// verificationLink 生成绑定用户当前邮箱的验证链接
+func verificationLink(user *models.User) (string, error) {
+	rawToken, err := token.GenerateEmailVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return "", err
+	}
+
+	verifyURL := viper.GetString("email_verification.verify_url")
+	if verifyURL == "" {
+		verifyURL = "http://localhost:5173/verify-email?token=%s"
+	}
+	return fmt.Sprintf(verifyURL, url.QueryEscape(rawToken)), nil
+}
+
+// sendVerificationEmail 生成邮箱验证链接并异步发送验证邮件
+func sendVerificationEmail(user *models.User) error {
+	link, err := verificationLink(user)
+	if err != nil {
+		return err
+	}
+
+	sendVerificationMessage(user, mailer.Message{
+		To:      []string{user.Email},
+		Subject: "验证您的邮箱",
+		Body: fmt.Sprintf("%s，您好：\n\n感谢注册！请在 %d 小时内点击以下链接验证邮箱并激活账户：\n\n%s\n\n账户在 %d 小时内未完成验证将被自动删除。如果这不是您本人的操作，请忽略此邮件。",
+			user.Username, int(token.EmailVerificationTTL().Hours()), link, int(auth.UnverifiedAccountTTL().Hours())),
+	})
+	return nil
+}
+
+// sendEmailChangeVerification 向修改后的新邮箱异步发送验证邮件
+func sendEmailChangeVerification(user *models.User) error {
+	link, err := verificationLink(user)
+	if err != nil {
+		return err
+	}
+
+	sendVerificationMessage(user, mailer.Message{
+		To:      []string{user.Email},
+		Subject: "验证您的新邮箱",
+		Body: fmt.Sprintf("%s，您好：\n\n您的账户邮箱已修改为此地址。请在 %d 小时内点击以下链接完成验证：\n\n%s\n\n如果这不是您本人的操作，请立即修改密码并联系管理员。",
+			user.Username, int(token.EmailVerificationTTL().Hours()), link),
+	})
+	return nil
+}
+
+// sendVerificationMessage 异步发送验证邮件
+func sendVerificationMessage(user *models.User, msg mailer.Message) {
+	go func() {
+		if err := mailer.Send(msg); err != nil {
+			logger.Errorw("发送验证邮件失败", "error", err, "user_id", user.ID)
+		}
+	}()
+}
+
+// respondTooManyAttempts 返回 429 并通过 Retry-After 告知客户端需等待的秒数
+func respondTooManyAttempts(c *gin.Context, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+		Code:    http.StatusTooManyRequests,
+		Message: fmt.Sprintf("尝试次数过多，请在 %d 秒后重试", seconds),
+		Error:   reasonTooManyAttempts,
+	})
+}
+
+// loginLocked 检查客户端IP、登录标识以及用户ID（userID 非0时）是否处于锁定状态
+func loginLocked(c *gin.Context, identifier string, userID uint) (time.Duration, bool) {
+	if wait, locked := auth.IPLimiter.Check(auth.IPKey(c.ClientIP())); locked {
+		return wait, true
+	}
+	if wait, locked := auth.AccountLimiter.Check(auth.AccountKey(identifier)); locked {
+		return wait, true
+	}
+	if userID != 0 {
+		return auth.AccountLimiter.Check(auth.UserKey(userID))
+	}
+	return 0, false
+}
+
+// recordLoginFailure 记录一次登录失败，用户不存在时 userID 为0
+func recordLoginFailure(c *gin.Context, identifier string, userID uint) {
+	if _, locked := auth.IPLimiter.Fail(auth.IPKey(c.ClientIP())); locked {
+		logger.Warnw("客户端IP因多次登录失败被锁定", "ip", c.ClientIP())
+	}
+	auth.AccountLimiter.Fail(auth.AccountKey(identifier))
+	if userID != 0 {
+		if lockout, locked := auth.AccountLimiter.Fail(auth.UserKey(userID)); locked {
+			logger.Warnw("账户因多次登录失败被锁定", "user_id", userID, "lockout", lockout.String())
+		}
+	}
+}
+
+// clearLoginFailures 登录成功后清除账户的失败记录（IP 记录保留，避免被用来重置计数）
+func clearLoginFailures(identifier string, userID uint) {
+	auth.AccountLimiter.Reset(auth.AccountKey(identifier))
+	auth.AccountLimiter.Reset(auth.UserKey(userID))
+}
+
+// completeLogin 为已通过认证的用户创建会话，并返回登录成功响应
+func completeLogin(c *gin.Context, user *models.User) {
+	// 清除密码字段后返回用户信息
+	user.PasswordHash = ""
+
+	logger.Infow("用户登录成功", "user_id", user.ID, "username", user.Username)
+
+	// 生成访问令牌和刷新令牌
+	session, tokens, err := newUserSession(c, user.ID, "")
+	if err != nil {
+		logger.Errorw("生成访问令牌失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "生成令牌失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 保存会话（刷新令牌只保存哈希）
+	if err := dao.CreateSession(session); err != nil {
+		logger.Errorw("创建用户会话失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "创建会话失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 根据接口要求，登录成功响应格式为 HTTP 200
+	response := models.LoginResponse{
+		Code:    http.StatusOK,
+		Message: "登录成功",
+	}
+	response.Data.AccessToken = tokens.AccessToken
+	response.Data.RefreshToken = tokens.RefreshToken
+	response.Data.User = user
+	c.JSON(http.StatusOK, response)
+}
+
+// finishLogin 完成已通过第一因素认证的登录：启用了二次验证的用户返回挑战，否则直接签发令牌
+func finishLogin(c *gin.Context, user *models.User) {
+	// 只要存在已启用的二次验证就必须再提交验证码，不论当前用户类型
+	enabled, err := auth.MFAEnabled(user.ID)
+	if err != nil {
+		logger.Errorw("查询二次验证配置失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "登录失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if enabled {
+		respondMFAChallenge(c, user)
+		return
+	}
+
+	completeLogin(c, user)
+}
+
+// respondMFAChallenge 返回二次验证挑战，客户端需携带挑战令牌和验证码调用 /auth/mfa/verify
+func respondMFAChallenge(c *gin.Context, user *models.User) {
+	challengeToken, err := auth.IssueMFAChallenge(user.ID)
+	if err != nil {
+		logger.Errorw("生成二次验证挑战令牌失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "生成令牌失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Infow("用户需要二次验证", "user_id", user.ID)
+
+	response := models.MFAChallengeResponse{
+		Code:    http.StatusOK,
+		Message: "需要二次验证",
+	}
+	response.Data.MFARequired = true
+	response.Data.ChallengeToken = challengeToken
+	response.Data.ExpiresIn = int64(token.MFAChallengeTTL.Seconds())
+	c.JSON(http.StatusOK, response)
+}
@@ -2,96 +2,53 @@ package controller
 
 import (
 	"go-web/internal/dao"
-	"go-web/internal/models"
-	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GetBrandSales 获取所有品牌销售数据
+// GetBrandSales 获取品牌销售数据，支持排序、分页和过滤
 func GetBrandSales(c *gin.Context) {
-	// 从数据库获取数据
-	brandSales, err := dao.GetBrandSales()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "获取品牌销售数据失败",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 返回成功响应
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "获取成功",
-		Data:    brandSales,
-	})
+	respondAnalytics(c, analyticsSpec{
+		sortFields:  brandSortFields,
+		nameColumn:  "brand_name",
+		defaultSort: "total_sales:desc",
+		failMessage: "获取品牌销售数据失败",
+		values:      dao.BrandSalesValues,
+	}, dao.ListBrandSales)
 }
 
-// GetTopBrandSales 获取前N名品牌销售数据
+// GetTopBrandSales 获取前N名品牌销售数据，默认前20名
 func GetTopBrandSales(c *gin.Context) {
-	// 获取查询参数，默认为前20名
-	limitStr := c.DefaultQuery("limit", "20")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 20 // 默认显示前20名
-	}
-
-	// 限制最大数量，避免性能问题
-	if limit > 100 {
-		limit = 100
-	}
-
-	// 从数据库获取数据
-	brandSales, err := dao.GetTopBrandSales(limit)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "获取品牌销售数据失败",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 返回成功响应
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "获取成功",
-		Data:    brandSales,
-	})
+	respondAnalytics(c, analyticsSpec{
+		sortFields:      brandSortFields,
+		nameColumn:      "brand_name",
+		defaultSort:     "total_sales:desc",
+		fixedSort:       true,
+		defaultPageSize: 20,
+		failMessage:     "获取品牌销售数据失败",
+		values:          dao.BrandSalesValues,
+	}, dao.ListBrandSales)
 }
 
-// GetLastBrandSales 获取后N名品牌销售数据
+// GetLastBrandSales 获取后N名品牌销售数据，默认后20名
 func GetLastBrandSales(c *gin.Context) {
-	// 获取查询参数，默认为后20名
-	limitStr := c.DefaultQuery("limit", "20")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 20 // 默认显示后20名
-	}
-
-	// 限制最大数量，避免性能问题
-	if limit > 100 {
-		limit = 100
-	}
+	respondAnalytics(c, analyticsSpec{
+		sortFields:      brandSortFields,
+		nameColumn:      "brand_name",
+		defaultSort:     "total_sales:asc",
+		fixedSort:       true,
+		defaultPageSize: 20,
+		failMessage:     "获取品牌销售数据失败",
+		values:          dao.BrandSalesValues,
+	}, dao.ListBrandSales)
+}
 
-	// 从数据库获取数据
-	brandSales, err := dao.GetLastBrandSales(limit)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "获取品牌销售数据失败",
-			Error:   err.Error(),
-		})
-		return
-	}
+// GetBrandTrend 获取品牌月度销售趋势，支持 from/to 年月区间、指定名称和前N个序列
+func GetBrandTrend(c *gin.Context) {
+	respondTrend(c, "获取品牌销售趋势失败", dao.BrandSalesTrend)
+}
 
-	// 返回成功响应
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "获取成功",
-		Data:    brandSales,
-	})
+// GetBrandComparison 获取各品牌两个年月的对比（同比、环比或指定年月）
+func GetBrandComparison(c *gin.Context) {
+	respondComparison(c, "获取品牌销售对比失败", dao.BrandSalesByPeriod)
 }
@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// breakdownDimensions 交叉分析允许的维度，参数名 -> 明细表列
+var breakdownDimensions = map[string]string{
+	"brand":       "brand_name",
+	"series":      "series",
+	"model":       "model",
+	"city":        "city",
+	"car_level":   "car_level",
+	"energy_type": "energy_type",
+	"period":      "period",
+}
+
+// breakdownMeasures 交叉分析允许的度量，参数名 -> 聚合表达式
+var breakdownMeasures = map[string]string{
+	"sales":   "SUM(sales)",
+	"records": "COUNT(*)",
+}
+
+// GetBreakdown 按两个维度交叉汇总销量明细，返回带行列合计的矩阵，用于热力图和堆叠柱状图
+func GetBreakdown(c *gin.Context) {
+	var req models.BreakdownQuery
+	badRequest := func(err error) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		badRequest(err)
+		return
+	}
+	if req.Measure == "" {
+		req.Measure = "sales"
+	}
+	if req.RowTop == 0 {
+		req.RowTop = 20
+	}
+	if req.ColTop == 0 {
+		req.ColTop = 10
+	}
+
+	rowColumn, ok := breakdownDimensions[req.Rows]
+	if !ok {
+		badRequest(fmt.Errorf("不支持的行维度: %s", req.Rows))
+		return
+	}
+	colColumn, ok := breakdownDimensions[req.Cols]
+	if !ok {
+		badRequest(fmt.Errorf("不支持的列维度: %s", req.Cols))
+		return
+	}
+	if req.Rows == req.Cols {
+		badRequest(errors.New("rows 和 cols 不能是同一个维度"))
+		return
+	}
+	measure, ok := breakdownMeasures[req.Measure]
+	if !ok {
+		badRequest(fmt.Errorf("不支持的度量: %s", req.Measure))
+		return
+	}
+	if req.From != "" && req.To != "" && req.From > req.To {
+		badRequest(errors.New("from 不能晚于 to"))
+		return
+	}
+
+	cells, err := dao.CarSalesBreakdown(dao.BreakdownFilter{From: req.From, To: req.To}, rowColumn, colColumn, measure)
+	if err != nil {
+		logger.Errorw("获取交叉分析数据失败", "error", err, "rows", req.Rows, "cols", req.Cols)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "获取交叉分析数据失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	data := buildBreakdown(cells, req.RowTop, req.ColTop, req.Others, othersLabel(c))
+	data.Rows = req.Rows
+	data.Cols = req.Cols
+	data.Measure = req.Measure
+	data.From = req.From
+	data.To = req.To
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    data,
+	})
+}
+
+// buildBreakdown 将单元格列表整理为矩阵，行列按合计降序截取前N个
+// others 为 true 时未返回的行列合并为名为 othersName 的最后一行/列
+func buildBreakdown(cells []dao.BreakdownCell, rowTop, colTop int, others bool, othersName string) models.BreakdownData {
+	rowTotals := make(map[string]float64)
+	colTotals := make(map[string]float64)
+	var total float64
+	for _, cell := range cells {
+		rowTotals[cell.Row] += cell.Value
+		colTotals[cell.Col] += cell.Value
+		total += cell.Value
+	}
+
+	rowKeys, rowOthers := topKeys(rowTotals, rowTop, others)
+	colKeys, colOthers := topKeys(colTotals, colTop, others)
+	rowIndex := keyIndex(rowKeys)
+	colIndex := keyIndex(colKeys)
+	if rowOthers {
+		rowKeys = append(rowKeys, othersName)
+	}
+	if colOthers {
+		colKeys = append(colKeys, othersName)
+	}
+
+	data := models.BreakdownData{
+		RowKeys:   rowKeys,
+		ColKeys:   colKeys,
+		Cells:     make([][]float64, len(rowKeys)),
+		RowTotals: make([]float64, len(rowKeys)),
+		ColTotals: make([]float64, len(colKeys)),
+		Total:     total,
+		RowCount:  len(rowTotals),
+		ColCount:  len(colTotals),
+	}
+	for i := range data.Cells {
+		data.Cells[i] = make([]float64, len(colKeys))
+	}
+
+	// 行列合计使用全部数据，不受列（行）截取影响
+	for i, key := range rowKeys {
+		if rowOthers && i == len(rowKeys)-1 {
+			break
+		}
+		data.RowTotals[i] = rowTotals[key]
+	}
+	for j, key := range colKeys {
+		if colOthers && j == len(colKeys)-1 {
+			break
+		}
+		data.ColTotals[j] = colTotals[key]
+	}
+
+	for _, cell := range cells {
+		i, rowOK := rowIndex[cell.Row]
+		if !rowOK && rowOthers {
+			i, rowOK = len(rowKeys)-1, true
+			data.RowTotals[i] += cell.Value
+		}
+		j, colOK := colIndex[cell.Col]
+		if !colOK && colOthers {
+			j, colOK = len(colKeys)-1, true
+			data.ColTotals[j] += cell.Value
+		}
+		if rowOK && colOK {
+			data.Cells[i][j] += cell.Value
+		}
+	}
+	return data
+}
+
+// topKeys 按合计降序（相同时按名称）返回前 top 个键，第二个返回值表示是否需要“其他”
+func topKeys(totals map[string]float64, top int, others bool) ([]string, bool) {
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if totals[keys[i]] != totals[keys[j]] {
+			return totals[keys[i]] > totals[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) <= top {
+		return keys, false
+	}
+	return keys[:top], others
+}
+
+// keyIndex 建立键到下标的映射
+func keyIndex(keys []string) map[string]int {
+	index := make(map[string]int, len(keys))
+	for i, key := range keys {
+		index[key] = i
+	}
+	return index
+}
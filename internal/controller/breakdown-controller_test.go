@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// breakdownCells 行合计 A=15 B=11 C=2，列合计 x=13 z=9 y=6
+var breakdownCells = []dao.BreakdownCell{
+	{Row: "A", Col: "x", Value: 10},
+	{Row: "A", Col: "y", Value: 5},
+	{Row: "B", Col: "x", Value: 3},
+	{Row: "B", Col: "z", Value: 8},
+	{Row: "C", Col: "y", Value: 1},
+	{Row: "C", Col: "z", Value: 1},
+}
+
+func TestBuildBreakdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		rowTop int
+		colTop int
+		others bool
+		want   models.BreakdownData
+	}{
+		{
+			name: "不截取", rowTop: 5, colTop: 5,
+			want: models.BreakdownData{
+				RowKeys:   []string{"A", "B", "C"},
+				ColKeys:   []string{"x", "z", "y"},
+				Cells:     [][]float64{{10, 0, 5}, {3, 8, 0}, {0, 1, 1}},
+				RowTotals: []float64{15, 11, 2},
+				ColTotals: []float64{13, 9, 6},
+			},
+		},
+		{
+			// 截取后的行列合计仍包含被截掉的列（行）
+			name: "截取不合并", rowTop: 2, colTop: 2,
+			want: models.BreakdownData{
+				RowKeys:   []string{"A", "B"},
+				ColKeys:   []string{"x", "z"},
+				Cells:     [][]float64{{10, 0}, {3, 8}},
+				RowTotals: []float64{15, 11},
+				ColTotals: []float64{13, 9},
+			},
+		},
+		{
+			name: "截取并合并为其他", rowTop: 2, colTop: 2, others: true,
+			want: models.BreakdownData{
+				RowKeys:   []string{"A", "B", "其他"},
+				ColKeys:   []string{"x", "z", "其他"},
+				Cells:     [][]float64{{10, 0, 5}, {3, 8, 0}, {0, 1, 1}},
+				RowTotals: []float64{15, 11, 2},
+				ColTotals: []float64{13, 9, 6},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.want.Total, tt.want.RowCount, tt.want.ColCount = 28, 3, 3
+		got := buildBreakdown(breakdownCells, tt.rowTop, tt.colTop, tt.others, "其他")
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %+v\n want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildBreakdownEmpty(t *testing.T) {
+	got := buildBreakdown(nil, 20, 10, true, "其他")
+	if len(got.RowKeys) != 0 || len(got.ColKeys) != 0 || len(got.Cells) != 0 || got.Total != 0 {
+		t.Fatalf("空数据: %+v", got)
+	}
+}
+
+func TestTopKeys(t *testing.T) {
+	totals := map[string]float64{"b": 1, "a": 1, "c": 2}
+
+	keys, others := topKeys(totals, 2, true)
+	if !reflect.DeepEqual(keys, []string{"c", "a"}) || !others {
+		t.Errorf("topKeys = %v, %v", keys, others)
+	}
+	if _, others := topKeys(totals, 2, false); others {
+		t.Error("未要求合并时不应返回其他")
+	}
+	if keys, others := topKeys(totals, 3, true); len(keys) != 3 || others {
+		t.Errorf("未截取时 topKeys = %v, %v", keys, others)
+	}
+}
+
+func TestGetBreakdownValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, query := range []string{
+		"cols=brand",
+		"rows=brand&cols=brand",
+		"rows=province&cols=brand",
+		"rows=brand&cols=city&measure=profit",
+		"rows=brand&cols=city&from=2024-05&to=2024-01",
+		"rows=brand&cols=city&row_top=500",
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/breakdown?"+query, nil)
+		GetBreakdown(c)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: code = %d, 期望 400", query, w.Code)
+		}
+	}
+}
@@ -2,96 +2,53 @@ package controller
 
 import (
 	"go-web/internal/dao"
-	"go-web/internal/models"
-	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GetCarLevelDistribution 获取所有汽车级别分布数据
+// GetCarLevelDistribution 获取汽车级别分布数据，支持排序、分页和过滤
 func GetCarLevelDistribution(c *gin.Context) {
-	// 从数据库获取数据
-	carLevels, err := dao.GetCarLevelDistribution()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "获取汽车级别分布数据失败",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 返回成功响应
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "获取成功",
-		Data:    carLevels,
-	})
+	respondAnalytics(c, analyticsSpec{
+		sortFields:  carLevelSortFields,
+		nameColumn:  "car_level",
+		defaultSort: "car_count:desc",
+		failMessage: "获取汽车级别分布数据失败",
+		values:      dao.CarLevelDistributionValues,
+	}, dao.ListCarLevelDistribution)
 }
 
-// GetTopCarLevels 获取前N名汽车级别分布数据
+// GetTopCarLevels 获取前N名汽车级别分布数据，默认前20名
 func GetTopCarLevels(c *gin.Context) {
-	// 获取查询参数，默认为前20名
-	limitStr := c.DefaultQuery("limit", "20")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 20 // 默认显示前20名
-	}
-
-	// 限制最大数量，避免性能问题
-	if limit > 100 {
-		limit = 100
-	}
-
-	// 从数据库获取数据
-	carLevels, err := dao.GetTopCarLevels(limit)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "获取汽车级别分布数据失败",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 返回成功响应
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "获取成功",
-		Data:    carLevels,
-	})
+	respondAnalytics(c, analyticsSpec{
+		sortFields:      carLevelSortFields,
+		nameColumn:      "car_level",
+		defaultSort:     "car_count:desc",
+		fixedSort:       true,
+		defaultPageSize: 20,
+		failMessage:     "获取汽车级别分布数据失败",
+		values:          dao.CarLevelDistributionValues,
+	}, dao.ListCarLevelDistribution)
 }
 
-// GetLastCarLevels 获取后N名汽车级别分布数据
+// GetLastCarLevels 获取后N名汽车级别分布数据，默认后20名
 func GetLastCarLevels(c *gin.Context) {
-	// 获取查询参数，默认为后20名
-	limitStr := c.DefaultQuery("limit", "20")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 20 // 默认显示后20名
-	}
-
-	// 限制最大数量，避免性能问题
-	if limit > 100 {
-		limit = 100
-	}
+	respondAnalytics(c, analyticsSpec{
+		sortFields:      carLevelSortFields,
+		nameColumn:      "car_level",
+		defaultSort:     "car_count:asc",
+		fixedSort:       true,
+		defaultPageSize: 20,
+		failMessage:     "获取汽车级别分布数据失败",
+		values:          dao.CarLevelDistributionValues,
+	}, dao.ListCarLevelDistribution)
+}
 
-	// 从数据库获取数据
-	carLevels, err := dao.GetLastCarLevels(limit)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "获取汽车级别分布数据失败",
-			Error:   err.Error(),
-		})
-		return
-	}
+// GetCarLevelTrend 获取汽车级别月度分布趋势，支持 from/to 年月区间、指定名称和前N个序列
+func GetCarLevelTrend(c *gin.Context) {
+	respondTrend(c, "获取汽车级别趋势失败", dao.CarLevelTrend)
+}
 
-	// 返回成功响应
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "获取成功",
-		Data:    carLevels,
-	})
+// GetCarLevelComparison 获取各汽车级别两个年月的对比（同比、环比或指定年月）
+func GetCarLevelComparison(c *gin.Context) {
+	respondComparison(c, "获取汽车级别对比失败", dao.CarLevelByPeriod)
 }
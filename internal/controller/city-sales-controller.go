@@ -2,63 +2,40 @@ package controller
 
 import (
 	"go-web/internal/dao"
-	"go-web/internal/models"
-	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GetCitySales 获取所有城市销售数据
+// GetCitySales 获取城市销售数据，支持排序、分页和过滤
 func GetCitySales(c *gin.Context) {
-	// 从数据库获取数据
-	citySales, err := dao.GetCitySales()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "获取城市销售数据失败",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 返回成功响应
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "获取成功",
-		Data:    citySales,
-	})
+	respondAnalytics(c, analyticsSpec{
+		sortFields:  citySortFields,
+		nameColumn:  "city",
+		defaultSort: "sales:desc",
+		failMessage: "获取城市销售数据失败",
+		values:      dao.CitySalesValues,
+	}, dao.ListCitySales)
 }
 
-// GetTopCitySales 获取前N名城市销售数据
+// GetTopCitySales 获取前N名城市销售数据，默认前10名
 func GetTopCitySales(c *gin.Context) {
-	// 获取查询参数，默认为前10名
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10 // 默认显示前10名
-	}
-
-	// 限制最大数量，避免性能问题
-	if limit > 100 {
-		limit = 100
-	}
+	respondAnalytics(c, analyticsSpec{
+		sortFields:      citySortFields,
+		nameColumn:      "city",
+		defaultSort:     "sales:desc",
+		fixedSort:       true,
+		defaultPageSize: 10,
+		failMessage:     "获取城市销售数据失败",
+		values:          dao.CitySalesValues,
+	}, dao.ListCitySales)
+}
 
-	// 从数据库获取数据
-	citySales, err := dao.GetTopCitySales(limit)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "获取城市销售数据失败",
-			Error:   err.Error(),
-		})
-		return
-	}
+// GetCityTrend 获取城市月度销售趋势，支持 from/to 年月区间、指定名称和前N个序列
+func GetCityTrend(c *gin.Context) {
+	respondTrend(c, "获取城市销售趋势失败", dao.CitySalesTrend)
+}
 
-	// 返回成功响应
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "获取成功",
-		Data:    citySales,
-	})
+// GetCityComparison 获取各城市两个年月的对比（同比、环比或指定年月）
+func GetCityComparison(c *gin.Context) {
+	respondComparison(c, "获取城市销售对比失败", dao.CitySalesByPeriod)
 }
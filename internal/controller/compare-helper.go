@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+	"go-web/pkg/stats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 对比方式
+const (
+	compareModeYoY    = "yoy"    // 同比：与去年同月对比
+	compareModeMoM    = "mom"    // 环比：与上月对比
+	compareModeCustom = "custom" // 与指定年月对比
+)
+
+// compareSortFields 对比结果允许排序的字段
+var compareSortFields = map[string]string{
+	"name":        "name",
+	"current":     "current",
+	"previous":    "previous",
+	"delta":       "delta",
+	"growth":      "growth",
+	"rank_change": "rank_change",
+}
+
+// comparePeriodOf 根据对比方式推算对比年月
+func comparePeriodOf(period, mode string) string {
+	t, _ := time.Parse(dao.PeriodLayout, period)
+	if mode == compareModeYoY {
+		return t.AddDate(-1, 0, 0).Format(dao.PeriodLayout)
+	}
+	return t.AddDate(0, -1, 0).Format(dao.PeriodLayout)
+}
+
+// compareValues 对比两个年月的数据，values 均按数值降序排列
+// 对比年月没有任何数据时无法区分新出现的项，本期各项的状态为 unknown
+func compareValues(current, previous []dao.AnalyticsValue) []models.ComparisonItem {
+	currentRanks := rankValues(current)
+	previousRanks := rankValues(previous)
+
+	initialStatus := models.ComparisonStatusNew
+	if len(previous) == 0 {
+		initialStatus = models.ComparisonStatusUnknown
+	}
+
+	items := make([]models.ComparisonItem, 0, len(current)+len(previous))
+	index := make(map[string]int, len(current))
+	for _, v := range current {
+		index[v.Name] = len(items)
+		rank := currentRanks[v.Name]
+		items = append(items, models.ComparisonItem{
+			Name:        v.Name,
+			Current:     v.Value,
+			CurrentRank: &rank,
+			Status:      initialStatus,
+		})
+	}
+	for _, v := range previous {
+		rank := previousRanks[v.Name]
+		i, ok := index[v.Name]
+		if !ok {
+			i = len(items)
+			items = append(items, models.ComparisonItem{Name: v.Name, Status: models.ComparisonStatusDropped})
+		} else {
+			items[i].Status = models.ComparisonStatusContinuing
+			change := rank - *items[i].CurrentRank
+			items[i].RankChange = &change
+		}
+		items[i].Previous = v.Value
+		items[i].PreviousRank = &rank
+	}
+
+	for i := range items {
+		items[i].Delta = items[i].Current - items[i].Previous
+		items[i].Growth = growthPercent(items[i].Current, items[i].Previous)
+	}
+	return items
+}
+
+// rankValues 计算按数值降序排列的数据项排名，数值相同排名相同
+func rankValues(values []dao.AnalyticsValue) map[string]int {
+	ranks := make(map[string]int, len(values))
+	rank := 0
+	for i, v := range values {
+		if i == 0 || v.Value != values[i-1].Value {
+			rank = i + 1
+		}
+		if _, exists := ranks[v.Name]; !exists {
+			ranks[v.Name] = rank
+		}
+	}
+	return ranks
+}
+
+// growthPercent 计算增长百分比，基数为0时无法计算，返回 nil
+func growthPercent(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	growth := stats.Round((current-previous)/previous*100, 4)
+	return &growth
+}
+
+// sortComparisonItems 按字段排序对比项，空值（如无法计算的增长率）始终排在最后
+func sortComparisonItems(items []models.ComparisonItem, field string, desc bool) {
+	key := func(item *models.ComparisonItem) (float64, bool) {
+		switch field {
+		case "current":
+			return item.Current, true
+		case "previous":
+			return item.Previous, true
+		case "delta":
+			return item.Delta, true
+		case "growth":
+			if item.Growth == nil {
+				return 0, false
+			}
+			return *item.Growth, true
+		case "rank_change":
+			if item.RankChange == nil {
+				return 0, false
+			}
+			return float64(*item.RankChange), true
+		}
+		return 0, true
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := &items[i], &items[j]
+		if field == "name" {
+			if desc {
+				return a.Name > b.Name
+			}
+			return a.Name < b.Name
+		}
+		av, aok := key(a)
+		bv, bok := key(b)
+		if aok != bok {
+			return aok
+		}
+		if av != bv {
+			if desc {
+				return av > bv
+			}
+			return av < bv
+		}
+		return a.Name < b.Name
+	})
+}
+
+// respondComparison 解析对比参数，查询两个年月的数据并返回对比结果
+func respondComparison(c *gin.Context, failMessage string, fetch func(period string) (string, []dao.AnalyticsValue, error)) {
+	var req models.CompareQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = compareModeMoM
+	}
+
+	badRequest := func(err error) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+	}
+	if req.Mode == compareModeCustom && req.ComparePeriod == "" {
+		badRequest(errors.New("mode=custom 时必须指定 compare_period"))
+		return
+	}
+	if req.Mode != compareModeCustom && req.ComparePeriod != "" {
+		badRequest(errors.New("compare_period 仅在 mode=custom 时有效"))
+		return
+	}
+	field, desc, err := parseSort(req.Sort, compareSortFields, "current:desc")
+	if err != nil {
+		badRequest(err)
+		return
+	}
+
+	serverError := func(err error) {
+		logger.Errorw(failMessage, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: failMessage,
+			Error:   err.Error(),
+		})
+	}
+
+	period, current, err := fetch(req.Period)
+	if err != nil {
+		serverError(err)
+		return
+	}
+	if period == "" || len(current) == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "暂无月度数据",
+		})
+		return
+	}
+
+	comparePeriod := req.ComparePeriod
+	if req.Mode != compareModeCustom {
+		comparePeriod = comparePeriodOf(period, req.Mode)
+	}
+	_, previous, err := fetch(comparePeriod)
+	if err != nil {
+		serverError(err)
+		return
+	}
+	// 指定的对比年月没有数据时直接报错；同比/环比缺少上期数据时通过 previous_available 标明
+	if len(previous) == 0 && req.Mode == compareModeCustom {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "对比年月暂无数据",
+		})
+		return
+	}
+
+	items := compareValues(current, previous)
+	data := models.ComparisonData{
+		Mode:              req.Mode,
+		Period:            period,
+		ComparePeriod:     comparePeriod,
+		PreviousAvailable: len(previous) > 0,
+		NewEntrants:       []string{},
+		Dropped:           []string{},
+	}
+	for _, item := range items {
+		data.Current += item.Current
+		data.Previous += item.Previous
+		switch item.Status {
+		case models.ComparisonStatusNew:
+			data.NewEntrants = append(data.NewEntrants, item.Name)
+		case models.ComparisonStatusDropped:
+			data.Dropped = append(data.Dropped, item.Name)
+		}
+	}
+	data.Delta = data.Current - data.Previous
+	data.Growth = growthPercent(data.Current, data.Previous)
+
+	sortComparisonItems(items, field, desc)
+	if req.Limit > 0 && len(items) > req.Limit {
+		items = items[:req.Limit]
+	}
+	data.Items = items
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    data,
+	})
+}
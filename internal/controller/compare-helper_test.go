@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestRankValuesTies(t *testing.T) {
+	ranks := rankValues([]dao.AnalyticsValue{
+		{Name: "A", Value: 100},
+		{Name: "B", Value: 80},
+		{Name: "C", Value: 80},
+		{Name: "D", Value: 50},
+		{Name: "E", Value: 50},
+		{Name: "F", Value: 50},
+		{Name: "G", Value: 10},
+	})
+	want := map[string]int{"A": 1, "B": 2, "C": 2, "D": 4, "E": 4, "F": 4, "G": 7}
+	if !reflect.DeepEqual(ranks, want) {
+		t.Fatalf("rankValues = %v, 期望 %v", ranks, want)
+	}
+
+	if ranks := rankValues(nil); len(ranks) != 0 {
+		t.Fatalf("空数据的排名 = %v", ranks)
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	current := []dao.AnalyticsValue{{Name: "A", Value: 150}, {Name: "B", Value: 100}, {Name: "C", Value: 40}}
+	previous := []dao.AnalyticsValue{{Name: "B", Value: 120}, {Name: "A", Value: 100}, {Name: "D", Value: 30}, {Name: "C", Value: 0}}
+
+	items := compareValues(current, previous)
+	byName := make(map[string]models.ComparisonItem, len(items))
+	for _, item := range items {
+		byName[item.Name] = item
+	}
+	if len(byName) != 4 {
+		t.Fatalf("对比项 = %+v", items)
+	}
+
+	a := byName["A"]
+	if a.Status != models.ComparisonStatusContinuing || a.Delta != 50 || a.Growth == nil || *a.Growth != 50 {
+		t.Errorf("A = %+v", a)
+	}
+	if !reflect.DeepEqual(a.RankChange, intPtr(1)) {
+		t.Errorf("A 排名变化 = %v, 期望上升1位", a.RankChange)
+	}
+
+	b := byName["B"]
+	if b.Delta != -20 || !reflect.DeepEqual(b.RankChange, intPtr(-1)) {
+		t.Errorf("B = %+v", b)
+	}
+
+	// 上期为0时无法计算增长率
+	if c := byName["C"]; c.Status != models.ComparisonStatusContinuing || c.Growth != nil {
+		t.Errorf("C = %+v", c)
+	}
+
+	d := byName["D"]
+	if d.Status != models.ComparisonStatusDropped || d.CurrentRank != nil || d.RankChange != nil || d.Delta != -30 {
+		t.Errorf("D = %+v", d)
+	}
+
+	// 只出现在本期的项为新出现
+	items = compareValues(current, previous[:1])
+	for _, item := range items {
+		if item.Name == "A" && item.Status != models.ComparisonStatusNew {
+			t.Errorf("A 状态 = %s, 期望 new", item.Status)
+		}
+	}
+}
+
+func TestCompareValuesWithoutPrevious(t *testing.T) {
+	items := compareValues([]dao.AnalyticsValue{{Name: "A", Value: 10}, {Name: "B", Value: 5}}, nil)
+	for _, item := range items {
+		if item.Status != models.ComparisonStatusUnknown || item.Growth != nil || item.PreviousRank != nil {
+			t.Errorf("上期无数据时 %s = %+v", item.Name, item)
+		}
+	}
+}
+
+func TestSortComparisonItemsNilLast(t *testing.T) {
+	growth := func(v float64) *float64 { return &v }
+	items := []models.ComparisonItem{
+		{Name: "nil-b", Current: 5},
+		{Name: "low", Current: 1, Growth: growth(-10), RankChange: intPtr(-2)},
+		{Name: "nil-a", Current: 5},
+		{Name: "high", Current: 9, Growth: growth(30), RankChange: intPtr(3)},
+	}
+	names := func(items []models.ComparisonItem) []string {
+		out := make([]string, 0, len(items))
+		for _, item := range items {
+			out = append(out, item.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		field string
+		desc  bool
+		want  []string
+	}{
+		{field: "growth", desc: true, want: []string{"high", "low", "nil-a", "nil-b"}},
+		{field: "growth", desc: false, want: []string{"low", "high", "nil-a", "nil-b"}},
+		{field: "rank_change", desc: true, want: []string{"high", "low", "nil-a", "nil-b"}},
+		{field: "current", desc: true, want: []string{"high", "nil-a", "nil-b", "low"}},
+		{field: "name", desc: false, want: []string{"high", "low", "nil-a", "nil-b"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]models.ComparisonItem(nil), items...)
+		sortComparisonItems(sorted, tt.field, tt.desc)
+		if got := names(sorted); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s desc=%v: %v, 期望 %v", tt.field, tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestRespondComparisonWithoutComparePeriodData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.Sugar = zap.NewNop().Sugar()
+
+	data := map[string][]dao.AnalyticsValue{
+		"2024-03": {{Name: "A", Value: 10}, {Name: "B", Value: 5}},
+		"2024-02": {{Name: "A", Value: 8}},
+	}
+	fetch := func(period string) (string, []dao.AnalyticsValue, error) {
+		if period == "" {
+			period = "2024-03"
+		}
+		return period, data[period], nil
+	}
+	call := func(query string) (int, models.ComparisonData) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/compare?"+query, nil)
+		respondComparison(c, "获取对比失败", fetch)
+
+		var body struct {
+			Data models.ComparisonData `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body.Data
+	}
+
+	code, result := call("mode=mom")
+	if code != http.StatusOK || !result.PreviousAvailable || !reflect.DeepEqual(result.NewEntrants, []string{"B"}) {
+		t.Errorf("环比: code = %d, data = %+v", code, result)
+	}
+
+	// 同比缺少上期数据：返回本期数据并标明无法对比，不把所有项当作新出现
+	code, result = call("mode=yoy")
+	if code != http.StatusOK || result.PreviousAvailable || len(result.NewEntrants) != 0 || result.Growth != nil {
+		t.Errorf("同比: code = %d, data = %+v", code, result)
+	}
+
+	if code, _ := call("mode=custom&compare_period=2020-01"); code != http.StatusNotFound {
+		t.Errorf("指定的对比年月无数据: code = %d, 期望 404", code)
+	}
+	if code, _ := call("period=2019-01"); code != http.StatusNotFound {
+		t.Errorf("本期无数据: code = %d, 期望 404", code)
+	}
+}
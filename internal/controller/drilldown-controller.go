@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+	"go-web/pkg/stats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// drilldownLevel 层级结构中的一级
+type drilldownLevel struct {
+	name   string
+	column string // 明细表（d）关联城市参考表（r）后的列表达式
+}
+
+// drilldownHierarchies 支持下钻的层级结构
+// 城市参考表中没有的城市，大区和省份归入“未知”
+var drilldownHierarchies = map[string][]drilldownLevel{
+	"region": {
+		{name: "region", column: "COALESCE(r.region, '" + models.UnknownLabel + "')"},
+		{name: "province", column: "COALESCE(r.province, '" + models.UnknownLabel + "')"},
+		{name: "city", column: "d.city"},
+	},
+	"brand": {
+		{name: "brand", column: "d.brand_name"},
+		{name: "series", column: "d.series"},
+		{name: "model", column: "d.model"},
+	},
+}
+
+// drilldownSortFields 子节点允许排序的字段
+var drilldownSortFields = map[string]string{
+	"name":  "name",
+	"value": "value",
+}
+
+// drilldownRootName 下钻路径根节点的名称
+const drilldownRootName = "全部"
+
+// GetDrilldown 获取层级结构中某个节点的子节点及汇总值，附带从根节点开始的路径
+func GetDrilldown(c *gin.Context) {
+	var req models.DrilldownQuery
+	badRequest := func(err error) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		badRequest(err)
+		return
+	}
+
+	hierarchy := c.Param("hierarchy")
+	levels, ok := drilldownHierarchies[hierarchy]
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "层级结构不存在",
+			Error:   fmt.Sprintf("不支持的层级结构: %s", hierarchy),
+		})
+		return
+	}
+	path, err := drilldownPath(levels, req.Path)
+	if err != nil {
+		badRequest(err)
+		return
+	}
+	if req.From != "" && req.To != "" && req.From > req.To {
+		badRequest(errors.New("from 不能晚于 to"))
+		return
+	}
+	if req.Others && req.Top == 0 {
+		badRequest(errors.New("others 需要同时指定 top"))
+		return
+	}
+	field, desc, err := parseSort(req.Sort, drilldownSortFields, "value:desc")
+	if err != nil {
+		badRequest(err)
+		return
+	}
+
+	serverError := func(err error) {
+		logger.Errorw("获取下钻数据失败", "error", err, "hierarchy", hierarchy, "path", req.Path)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "获取下钻数据失败",
+			Error:   err.Error(),
+		})
+	}
+
+	filter := dao.BreakdownFilter{From: req.From, To: req.To}
+
+	// 路径上每一级分别汇总，使面包屑中的每个节点都带有自己的合计
+	breadcrumbs := make([]models.Breadcrumb, 0, len(path)+1)
+	for depth := 0; depth <= len(path); depth++ {
+		total, err := dao.CarSalesRollup(filter, path[:depth])
+		if err != nil {
+			serverError(err)
+			return
+		}
+		crumb := models.Breadcrumb{Name: drilldownRootName, Value: total}
+		if depth > 0 {
+			crumb.Level = levels[depth-1].name
+			crumb.Name = req.Path[depth-1]
+		}
+		breadcrumbs = append(breadcrumbs, crumb)
+	}
+
+	total := breadcrumbs[len(breadcrumbs)-1].Value
+	if len(path) > 0 && total == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "节点不存在",
+			Error:   "所选时间区间内没有该节点的数据",
+		})
+		return
+	}
+
+	data := models.DrilldownData{
+		Hierarchy:   hierarchy,
+		Levels:      make([]string, len(levels)),
+		Breadcrumbs: breadcrumbs,
+		Total:       total,
+		Children:    []models.DrilldownNode{},
+	}
+	for i, level := range levels {
+		data.Levels[i] = level.name
+	}
+
+	// 已经是最底层时没有子节点
+	if len(path) < len(levels) {
+		child := levels[len(path)]
+		values, err := dao.CarSalesDrilldown(filter, path, child.column)
+		if err != nil {
+			serverError(err)
+			return
+		}
+		data.Level = child.name
+		data.ChildCount = len(values)
+		data.Children = drilldownChildren(values, total, len(path)+1 < len(levels), field, desc, req.Top, req.Others, othersLabel(c))
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    data,
+	})
+}
+
+// drilldownPath 校验下钻路径并转换为逐级过滤条件，第 i 个节点名称匹配层级结构第 i 级的列
+func drilldownPath(levels []drilldownLevel, names []string) ([]dao.DrillCondition, error) {
+	if len(names) > len(levels) {
+		return nil, fmt.Errorf("path 最多 %d 级", len(levels))
+	}
+	path := make([]dao.DrillCondition, len(names))
+	for i, name := range names {
+		path[i] = dao.DrillCondition{Column: levels[i].column, Value: name}
+	}
+	return path, nil
+}
+
+// drilldownChildren 排序并截取子节点，计算占比，others 为 true 时未返回的子节点合并为“其他”
+// “其他”节点总是排在最后，并按合计最高的 top 个子节点截取，与排序方式无关
+func drilldownChildren(values []dao.AnalyticsValue, total float64, hasChildren bool, field string, desc bool, top int, others bool, othersName string) []models.DrilldownNode {
+	var grouped []dao.AnalyticsValue
+	if top > 0 && len(values) > top {
+		// values 已按数值降序排列
+		grouped = values[top:]
+		values = values[:top]
+	}
+
+	nodes := make([]models.DrilldownNode, 0, len(values)+1)
+	for _, v := range values {
+		nodes = append(nodes, models.DrilldownNode{
+			Name:        v.Name,
+			Value:       v.Value,
+			Share:       stats.Round(stats.Percent(v.Value, total), 4),
+			HasChildren: hasChildren,
+		})
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := &nodes[i], &nodes[j]
+		if field == "value" && a.Value != b.Value {
+			if desc {
+				return a.Value > b.Value
+			}
+			return a.Value < b.Value
+		}
+		if field == "name" && desc {
+			return a.Name > b.Name
+		}
+		return a.Name < b.Name
+	})
+
+	if others && len(grouped) > 0 {
+		var rest float64
+		for _, v := range grouped {
+			rest += v.Value
+		}
+		nodes = append(nodes, models.DrilldownNode{
+			Name:         othersName,
+			Value:        rest,
+			Share:        stats.Round(stats.Percent(rest, total), 4),
+			Others:       true,
+			GroupedCount: len(grouped),
+		})
+	}
+	return nodes
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go-web/internal/dao"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDrilldownPath(t *testing.T) {
+	levels := drilldownHierarchies["region"]
+
+	tests := []struct {
+		name    string
+		names   []string
+		columns []string
+		wantErr bool
+	}{
+		{name: "根节点", names: nil, columns: []string{}},
+		{name: "大区", names: []string{"华东"}, columns: []string{levels[0].column}},
+		{name: "到城市", names: []string{"华东", "江苏省", "南京"}, columns: []string{levels[0].column, levels[1].column, "d.city"}},
+		{name: "超过层级数", names: []string{"华东", "江苏省", "南京", "鼓楼区"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		path, err := drilldownPath(levels, tt.names)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: 应返回错误", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		columns := make([]string, len(path))
+		for i, cond := range path {
+			columns[i] = cond.Column
+			if cond.Value != tt.names[i] {
+				t.Errorf("%s: 第 %d 级的值 = %q", tt.name, i, cond.Value)
+			}
+		}
+		if !reflect.DeepEqual(columns, tt.columns) {
+			t.Errorf("%s: 列 = %v, 期望 %v", tt.name, columns, tt.columns)
+		}
+	}
+}
+
+func TestDrilldownPathUsesWhitelistedColumns(t *testing.T) {
+	// 节点名称只作为参数值，列表达式只能来自层级定义
+	path, err := drilldownPath(drilldownHierarchies["brand"], []string{"d.city", "1=1) OR (1=1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []dao.DrillCondition{
+		{Column: "d.brand_name", Value: "d.city"},
+		{Column: "d.series", Value: "1=1) OR (1=1"},
+	}
+	if !reflect.DeepEqual(path, want) {
+		t.Fatalf("path = %+v", path)
+	}
+}
+
+func TestGetDrilldownValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		hierarchy string
+		query     string
+		code      int
+	}{
+		{hierarchy: "province", code: http.StatusNotFound},
+		{hierarchy: "brand", query: "path=a&path=b&path=c&path=d", code: http.StatusBadRequest},
+		{hierarchy: "region", query: "path=", code: http.StatusBadRequest},
+		{hierarchy: "region", query: "from=2024-05&to=2024-01", code: http.StatusBadRequest},
+		{hierarchy: "region", query: "others=true", code: http.StatusBadRequest},
+		{hierarchy: "region", query: "sort=share:desc", code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/drilldown/"+tt.hierarchy+"?"+tt.query, nil)
+		c.Params = gin.Params{{Key: "hierarchy", Value: tt.hierarchy}}
+		GetDrilldown(c)
+		if w.Code != tt.code {
+			t.Errorf("%s?%s: code = %d, 期望 %d", tt.hierarchy, tt.query, w.Code, tt.code)
+		}
+	}
+}
+
+func TestDrilldownChildren(t *testing.T) {
+	values := []dao.AnalyticsValue{{Name: "c", Value: 50}, {Name: "a", Value: 30}, {Name: "b", Value: 15}, {Name: "d", Value: 5}}
+
+	nodes := drilldownChildren(values, 100, true, "name", false, 2, true, "其他")
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	// 按合计截取前 2 个后再按名称排序，“其他”总在最后
+	if !reflect.DeepEqual(names, []string{"a", "c", "其他"}) {
+		t.Fatalf("子节点 = %v", names)
+	}
+	if rest := nodes[2]; !rest.Others || rest.Value != 20 || rest.Share != 20 || rest.GroupedCount != 2 || rest.HasChildren {
+		t.Errorf("其他 = %+v", rest)
+	}
+	if !nodes[0].HasChildren || nodes[0].Share != 30 {
+		t.Errorf("a = %+v", nodes[0])
+	}
+
+	if nodes := drilldownChildren(values, 100, false, "value", true, 0, false, "其他"); len(nodes) != 4 || nodes[0].Name != "c" || nodes[0].HasChildren {
+		t.Errorf("不截取时 = %+v", nodes)
+	}
+}
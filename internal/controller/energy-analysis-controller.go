@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"net/http"
+	"sort"
+
+	"go-web/internal/analytics"
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// penetrationSortFields 分维度渗透率允许排序的字段
+var penetrationSortFields = map[string]string{
+	"name":        "name",
+	"total":       "total",
+	"nev":         "nev",
+	"penetration": "penetration",
+}
+
+// energyBreakdowns 各统计维度对应的数据来源
+var energyBreakdowns = map[string]func() ([]dao.EnergyBreakdownRow, error){
+	"city":      dao.CityEnergyBreakdown,
+	"car_level": dao.CarLevelEnergyBreakdown,
+}
+
+// GetEnergyAnalysis 能源结构分析：新能源渗透率、能源类型构成，可按城市或汽车级别分维度统计
+func GetEnergyAnalysis(c *gin.Context) {
+	var req models.EnergyAnalysisQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+	field, desc, err := parseSort(req.Sort, penetrationSortFields, "total:desc")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if req.Top == 0 {
+		req.Top = 20
+	}
+
+	serverError := func(err error) {
+		logger.Errorw("获取能源结构分析失败", "error", err, "by", req.By)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "获取能源结构分析失败",
+			Error:   err.Error(),
+		})
+	}
+
+	values, err := dao.EnergyDistributionValues(dao.AnalyticsFilter{})
+	if err != nil {
+		serverError(err)
+		return
+	}
+	analysis := analytics.AnalyzeEnergy(values)
+
+	if req.By != "" {
+		rows, err := energyBreakdowns[req.By]()
+		if err != nil {
+			serverError(err)
+			return
+		}
+		items := analytics.Penetration(rows)
+		sortPenetrationItems(items, field, desc)
+		if len(items) > req.Top {
+			items = items[:req.Top]
+		}
+		analysis.By = req.By
+		analysis.Breakdown = items
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    analysis,
+	})
+}
+
+// sortPenetrationItems 按字段排序分维度渗透率，值相同时按名称排序
+func sortPenetrationItems(items []models.PenetrationItem, field string, desc bool) {
+	key := func(item *models.PenetrationItem) float64 {
+		switch field {
+		case "total":
+			return item.Total
+		case "nev":
+			return item.NEV
+		case "penetration":
+			return item.Penetration
+		}
+		return 0
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := &items[i], &items[j]
+		if field != "name" {
+			if ka, kb := key(a), key(b); ka != kb {
+				if desc {
+					return ka > kb
+				}
+				return ka < kb
+			}
+			return a.Name < b.Name
+		}
+		if desc {
+			return a.Name > b.Name
+		}
+		return a.Name < b.Name
+	})
+}
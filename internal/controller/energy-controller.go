@@ -2,29 +2,27 @@ package controller
 
 import (
 	"go-web/internal/dao"
-	"go-web/internal/models"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GetEnergyDistribution 获取能源类型分布
+// GetEnergyDistribution 获取能源类型分布，支持排序、分页和过滤
 func GetEnergyDistribution(c *gin.Context) {
-	// 从数据库获取数据
-	energyList, err := dao.GetEnergyDistribution()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "获取数据失败",
-			Error:   err.Error(),
-		})
-		return
-	}
+	respondAnalytics(c, analyticsSpec{
+		sortFields:  energySortFields,
+		nameColumn:  "energy_type",
+		defaultSort: "count:desc",
+		failMessage: "获取数据失败",
+		values:      dao.EnergyDistributionValues,
+	}, dao.ListEnergyDistribution)
+}
+
+// GetEnergyTrend 获取能源类型月度分布趋势，支持 from/to 年月区间、指定名称和前N个序列
+func GetEnergyTrend(c *gin.Context) {
+	respondTrend(c, "获取能源类型趋势失败", dao.EnergyTrend)
+}
 
-	// 返回成功响应
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "获取成功",
-		Data:    energyList,
-	})
+// GetEnergyComparison 获取各能源类型两个年月的对比（同比、环比或指定年月）
+func GetEnergyComparison(c *gin.Context) {
+	respondComparison(c, "获取能源类型对比失败", dao.EnergyByPeriod)
 }
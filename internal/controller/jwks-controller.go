@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"net/http"
+
+	"go-web/pkg/token"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler 发布用于验证访问令牌的公钥集合（RFC 7517），按标准格式直接返回，不包装统一响应
+func JWKSHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, token.PublicJWKS())
+}
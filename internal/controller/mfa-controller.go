@@ -0,0 +1,298 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"go-web/internal/auth"
+	"go-web/internal/dao"
+	"go-web/internal/middleware"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 二次验证失败原因
+const (
+	reasonMFAChallengeInvalid = "mfa_challenge_invalid"
+	reasonMFACodeInvalid      = "mfa_code_invalid"
+)
+
+// MFAVerifyHandler 使用挑战令牌和验证码完成登录
+func MFAVerifyHandler(c *gin.Context) {
+	var req models.MFAVerifyRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("二次验证请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	invalidChallenge := models.ErrorResponse{
+		Code:    http.StatusUnauthorized,
+		Message: "登录已过期，请重新登录",
+		Error:   reasonMFAChallengeInvalid,
+	}
+
+	challenge, err := auth.ParseMFAChallenge(req.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, invalidChallenge)
+		return
+	}
+
+	// 验证码失败次数与密码失败共用账户锁定
+	if wait, locked := auth.AccountLimiter.Check(auth.UserKey(challenge.UserID)); locked {
+		respondTooManyAttempts(c, wait)
+		return
+	}
+
+	user, err := dao.GetUserByID(challenge.UserID)
+	if err != nil || user.Status != models.UserStatusActive || user.PasswordResetRequired {
+		c.JSON(http.StatusUnauthorized, invalidChallenge)
+		return
+	}
+
+	if err := auth.VerifyMFA(user.ID, req.Code); err != nil {
+		if errors.Is(err, auth.ErrMFAInvalidCode) || errors.Is(err, auth.ErrMFANotEnrolled) {
+			auth.AccountLimiter.Fail(auth.UserKey(user.ID))
+			logger.Warnw("二次验证码错误", "user_id", user.ID, "ip", c.ClientIP())
+			recordUserAudit(c, user, models.AuditActionMFAVerify, models.AuditResultFailure, gin.H{"reason": reasonMFACodeInvalid})
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "验证码错误",
+				Error:   reasonMFACodeInvalid,
+			})
+			return
+		}
+		logger.Errorw("二次验证失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "二次验证失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 挑战令牌只能兑换一次，防止截获的令牌在有效期内被重放
+	if err := auth.ConsumeMFAChallenge(challenge); err != nil {
+		if errors.Is(err, auth.ErrMFAChallengeUsed) {
+			logger.Warnw("二次验证挑战令牌被重复使用", "user_id", user.ID, "ip", c.ClientIP())
+			recordUserAudit(c, user, models.AuditActionMFAVerify, models.AuditResultFailure, gin.H{"reason": reasonMFAChallengeInvalid})
+			c.JSON(http.StatusUnauthorized, invalidChallenge)
+			return
+		}
+		logger.Errorw("记录挑战令牌使用失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "二次验证失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	auth.AccountLimiter.Reset(auth.UserKey(user.ID))
+	recordUserAudit(c, user, models.AuditActionMFAVerify, models.AuditResultSuccess, nil)
+	completeLogin(c, user)
+}
+
+// GetMFAStatusHandler 获取当前用户的二次验证状态
+func GetMFAStatusHandler(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+
+	enabled, err := auth.MFAEnabled(user.ID)
+	if err != nil {
+		logger.Errorw("查询二次验证配置失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "获取二次验证状态失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var remaining int64
+	if enabled {
+		if remaining, err = dao.CountRecoveryCodes(user.ID); err != nil {
+			logger.Errorw("统计恢复码失败", "error", err, "user_id", user.ID)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data: gin.H{
+			"enabled":                  enabled,
+			"recovery_codes_remaining": remaining,
+		},
+	})
+}
+
+// EnrollMFAHandler 开始绑定 TOTP（仅系统用户），返回密钥和二维码链接
+func EnrollMFAHandler(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+
+	if user.UserType != models.UserTypeSystem {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "仅系统用户可以启用二次验证",
+		})
+		return
+	}
+
+	enabled, err := auth.MFAEnabled(user.ID)
+	if err != nil {
+		logger.Errorw("查询二次验证配置失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "绑定二次验证失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if enabled {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "已启用二次验证，请先关闭后再重新绑定",
+		})
+		return
+	}
+
+	secret, uri, err := auth.EnrollMFA(user)
+	if err != nil {
+		logger.Errorw("生成二次验证密钥失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "绑定二次验证失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "请使用验证器应用扫描二维码，并提交验证码完成绑定",
+		Data: gin.H{
+			"secret":           secret,
+			"provisioning_uri": uri,
+		},
+	})
+}
+
+// ConfirmMFAHandler 提交验证码确认绑定，成功后返回一次性恢复码（只显示这一次）
+func ConfirmMFAHandler(c *gin.Context) {
+	var req models.MFACodeRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+
+	codes, err := auth.ConfirmMFA(user.ID, req.Code)
+	if err != nil {
+		if errors.Is(err, auth.ErrMFAInvalidCode) || errors.Is(err, auth.ErrMFANotEnrolled) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "验证码错误",
+				Error:   reasonMFACodeInvalid,
+			})
+			return
+		}
+		logger.Errorw("启用二次验证失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "启用二次验证失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Infow("用户已启用二次验证", "user_id", user.ID)
+	recordUserAudit(c, user, models.AuditActionMFAEnable, models.AuditResultSuccess, nil)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "二次验证已启用，请妥善保存恢复码",
+		Data: gin.H{
+			"recovery_codes": codes,
+		},
+	})
+}
+
+// DisableMFAHandler 使用验证码或恢复码关闭二次验证
+func DisableMFAHandler(c *gin.Context) {
+	var req models.MFACodeRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+
+	// 与登录二次验证共用账户锁定，防止盗用会话后暴力尝试验证码
+	if wait, locked := auth.AccountLimiter.Check(auth.UserKey(user.ID)); locked {
+		recordUserAudit(c, user, models.AuditActionMFADisable, models.AuditResultFailure, gin.H{"reason": reasonTooManyAttempts})
+		respondTooManyAttempts(c, wait)
+		return
+	}
+
+	if err := auth.VerifyMFA(user.ID, req.Code); err != nil {
+		if errors.Is(err, auth.ErrMFAInvalidCode) || errors.Is(err, auth.ErrMFANotEnrolled) {
+			auth.AccountLimiter.Fail(auth.UserKey(user.ID))
+			logger.Warnw("关闭二次验证时验证码错误", "user_id", user.ID, "ip", c.ClientIP())
+			recordUserAudit(c, user, models.AuditActionMFADisable, models.AuditResultFailure, gin.H{"reason": reasonMFACodeInvalid})
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "验证码错误",
+				Error:   reasonMFACodeInvalid,
+			})
+			return
+		}
+		logger.Errorw("校验二次验证码失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "关闭二次验证失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	auth.AccountLimiter.Reset(auth.UserKey(user.ID))
+
+	if err := dao.DeleteUserMFA(user.ID); err != nil {
+		logger.Errorw("关闭二次验证失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "关闭二次验证失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Infow("用户已关闭二次验证", "user_id", user.ID)
+	recordUserAudit(c, user, models.AuditActionMFADisable, models.AuditResultSuccess, nil)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "二次验证已关闭",
+		Data:    gin.H{},
+	})
+}
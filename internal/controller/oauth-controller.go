@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"go-web/internal/auth"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// 保存登录请求绑定值的 Cookie，回调时要求与 state 对应，确保回调来自发起登录的浏览器
+const (
+	oauthStateCookie     = "oauth_state"
+	oauthStateCookiePath = "/api/v1/auth/oauth"
+)
+
+// setOAuthStateCookie 设置（maxAge 小于0时清除）登录请求绑定 Cookie
+func setOAuthStateCookie(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || viper.GetBool("oauth.cookie_secure")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, value, maxAge, oauthStateCookiePath, "", secure, true)
+}
+
+// OAuthProvidersHandler 获取已启用的第三方登录提供方
+func OAuthProvidersHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    gin.H{"providers": auth.OAuthProviderNames()},
+	})
+}
+
+// OAuthAuthorizeHandler 发起第三方登录，返回身份提供方的授权地址，前端跳转到该地址完成登录
+func OAuthAuthorizeHandler(c *gin.Context) {
+	provider := c.Param("provider")
+
+	authURL, state, err := auth.BeginOAuthLogin(c.Request.Context(), provider)
+	if err != nil {
+		if errors.Is(err, auth.ErrOAuthProviderNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "未配置该登录方式",
+			})
+			return
+		}
+		logger.Errorw("发起第三方登录失败", "error", err, "provider", provider)
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{
+			Code:    http.StatusBadGateway,
+			Message: "身份提供方暂不可用",
+			Error:   reasonOAuthLoginFailed,
+		})
+		return
+	}
+
+	setOAuthStateCookie(c, auth.OAuthStateBinding(state), int(auth.OAuthStateTTL().Seconds()))
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data: gin.H{
+			"authorization_url": authURL,
+			"state":             state,
+		},
+	})
+}
+
+// OAuthCallbackHandler 使用身份提供方回跳的授权码完成登录，签发与账号密码登录相同的令牌
+func OAuthCallbackHandler(c *gin.Context) {
+	var req models.OAuthCallbackRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("第三方登录回调参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	provider := c.Param("provider")
+
+	// 回调必须来自发起登录的浏览器，绑定 Cookie 只能使用一次
+	binding, _ := c.Cookie(oauthStateCookie)
+	setOAuthStateCookie(c, "", -1)
+	if !auth.OAuthStateBound(req.State, binding) {
+		logger.Warnw("第三方登录回调与发起登录的浏览器不一致", "provider", provider, "ip", c.ClientIP())
+		recordUserAudit(c, nil, models.AuditActionOAuthLogin, models.AuditResultFailure, gin.H{
+			"provider": provider,
+			"reason":   reasonOAuthStateInvalid,
+		})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "登录请求无效或已过期，请重新登录",
+			Error:   reasonOAuthStateInvalid,
+		})
+		return
+	}
+
+	user, err := auth.CompleteOAuthLogin(c.Request.Context(), provider, req.Code, req.State)
+	if err != nil {
+		recordUserAudit(c, nil, models.AuditActionOAuthLogin, models.AuditResultFailure, gin.H{
+			"provider": provider,
+			"error":    err.Error(),
+		})
+		switch {
+		case errors.Is(err, auth.ErrOAuthProviderNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "未配置该登录方式",
+			})
+		case errors.Is(err, auth.ErrOAuthStateInvalid):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "登录请求无效或已过期，请重新登录",
+				Error:   reasonOAuthStateInvalid,
+			})
+		case errors.Is(err, auth.ErrOAuthAccountNotLinked):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "该账户尚未开通，请联系管理员",
+				Error:   reasonOAuthNotLinked,
+			})
+		case errors.Is(err, auth.ErrOAuthEmailInUse):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "该邮箱已被其他账户使用",
+				Error:   reasonOAuthEmailInUse,
+			})
+		default:
+			logger.Errorw("第三方登录失败", "error", err, "provider", provider)
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "第三方登录失败",
+				Error:   reasonOAuthLoginFailed,
+			})
+		}
+		return
+	}
+
+	// 检查用户状态
+	if user.Status != models.UserStatusActive {
+		logger.Warnw("用户账户未激活", "user_id", user.ID, "status", user.Status)
+		recordUserAudit(c, user, models.AuditActionOAuthLogin, models.AuditResultFailure, gin.H{
+			"provider": provider,
+			"reason":   "user_inactive",
+		})
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "账户未激活，无法登录",
+		})
+		return
+	}
+
+	logger.Infow("第三方登录认证成功", "user_id", user.ID, "provider", provider)
+	recordUserAudit(c, user, models.AuditActionOAuthLogin, models.AuditResultSuccess, gin.H{"provider": provider})
+	finishLogin(c, user)
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseSort 解析 "字段:asc|desc" 格式的排序参数，字段必须在白名单内
+// allowed 为 参数字段 -> 数据库列 的映射；sort 为空时使用 defaultSort
+func parseSort(sort string, allowed map[string]string, defaultSort string) (column string, desc bool, err error) {
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	field, direction, _ := strings.Cut(sort, ":")
+	column, ok := allowed[field]
+	if !ok {
+		return "", false, fmt.Errorf("不支持的排序字段: %s", field)
+	}
+
+	switch strings.ToLower(direction) {
+	case "", "asc":
+		return column, false, nil
+	case "desc":
+		return column, true, nil
+	default:
+		return "", false, fmt.Errorf("不支持的排序方向: %s", direction)
+	}
+}
+
+// orderClause 生成 ORDER BY 子句
+func orderClause(column string, desc bool) string {
+	if desc {
+		return column + " DESC"
+	}
+	return column + " ASC"
+}
+
+// encodeCursor 将主键编码为不透明的游标
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+// decodeCursor 解析游标中的主键
+func decodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
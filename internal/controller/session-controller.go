@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"net/http"
+
+	"go-web/internal/auth"
+	"go-web/internal/middleware"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSessionsHandler 获取当前用户已登录的设备（会话）列表
+func ListSessionsHandler(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+	claims, _ := middleware.CurrentClaims(c)
+
+	respondSessions(c, user.ID, claims.SessionID)
+}
+
+// RevokeSessionHandler 移除当前用户的某个登录设备，移除当前会话等同于登出
+func RevokeSessionHandler(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+
+	revokeSession(c, user, models.SessionRevokeUser)
+}
+
+// AdminListSessionsHandler 获取指定用户的登录设备列表（管理员）
+func AdminListSessionsHandler(c *gin.Context) {
+	target, ok := loadTargetUser(c)
+	if !ok {
+		return
+	}
+
+	current := ""
+	if claims, ok := middleware.CurrentClaims(c); ok && claims.UserID == target.ID {
+		current = claims.SessionID
+	}
+	respondSessions(c, target.ID, current)
+}
+
+// AdminRevokeSessionHandler 移除指定用户的某个登录设备（管理员）
+func AdminRevokeSessionHandler(c *gin.Context) {
+	target, ok := loadTargetUser(c)
+	if !ok {
+		return
+	}
+
+	revokeSession(c, target, models.SessionRevokeAdmin)
+}
+
+// respondSessions 返回用户的会话列表
+func respondSessions(c *gin.Context, userID uint, currentFamilyID string) {
+	sessions, err := auth.ListSessions(userID, currentFamilyID)
+	if err != nil {
+		logger.Errorw("查询用户会话失败", "error", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "获取会话列表失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    sessions,
+	})
+}
+
+// revokeSession 吊销用户的单个会话族，会话ID取自路径参数 sessionId
+func revokeSession(c *gin.Context, user *models.User, reason string) {
+	sessionID := c.Param("sessionId")
+
+	revoked, err := auth.RevokeUserSession(user.ID, sessionID, reason)
+	if err != nil {
+		logger.Errorw("吊销会话失败", "error", err, "user_id", user.ID, "family_id", sessionID)
+		recordAudit(c, models.AuditActionSessionRevoke, "session", sessionID, models.AuditResultFailure, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "移除会话失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "会话不存在或已失效",
+		})
+		return
+	}
+
+	recordAudit(c, models.AuditActionSessionRevoke, "session", sessionID, models.AuditResultSuccess, gin.H{
+		"user_id": user.ID,
+		"reason":  reason,
+	})
+	logger.Infow("会话已移除", "user_id", user.ID, "family_id", sessionID, "reason", reason)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "移除成功",
+		Data:    gin.H{},
+	})
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTrendMonths 单次趋势查询允许的最大月份数
+const maxTrendMonths = 60
+
+// parseTrendQuery 解析趋势查询参数
+func parseTrendQuery(c *gin.Context) (dao.TrendFilter, error) {
+	var req models.TrendQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		return dao.TrendFilter{}, err
+	}
+
+	// 年月格式已由 binding 校验，YYYY-MM 可直接按字符串比较
+	if req.From != "" && req.To != "" {
+		if req.From > req.To {
+			return dao.TrendFilter{}, errors.New("from 不能晚于 to")
+		}
+		if monthsBetween(req.From, req.To) > maxTrendMonths {
+			return dao.TrendFilter{}, errors.New("查询区间不能超过60个月")
+		}
+	}
+
+	filter := dao.TrendFilter{
+		From:     req.From,
+		To:       req.To,
+		Contains: strings.TrimSpace(req.Contains),
+		Top:      req.Top,
+	}
+	for _, name := range strings.Split(req.Names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			filter.Names = append(filter.Names, name)
+		}
+	}
+	if filter.Top == 0 {
+		filter.Top = 10
+	}
+	return filter, nil
+}
+
+// monthsBetween 计算两个年月之间包含的月份数（含首尾）
+func monthsBetween(from, to string) int {
+	f, _ := time.Parse(dao.PeriodLayout, from)
+	t, _ := time.Parse(dao.PeriodLayout, to)
+	return (t.Year()-f.Year())*12 + int(t.Month()-f.Month()) + 1
+}
+
+// buildTrendData 将趋势查询结果整理为按月对齐的序列，缺失月份补0
+func buildTrendData(result *dao.TrendResult) models.TrendData {
+	data := models.TrendData{
+		From:    result.From,
+		To:      result.To,
+		Periods: []string{},
+		Series:  []models.TrendSeries{},
+		Totals:  []float64{},
+	}
+	if result.From == "" || result.From > result.To {
+		return data
+	}
+
+	index := make(map[string]int)
+	start, _ := time.Parse(dao.PeriodLayout, result.From)
+	for m := start; m.Format(dao.PeriodLayout) <= result.To; m = m.AddDate(0, 1, 0) {
+		index[m.Format(dao.PeriodLayout)] = len(data.Periods)
+		data.Periods = append(data.Periods, m.Format(dao.PeriodLayout))
+	}
+	data.Totals = make([]float64, len(data.Periods))
+
+	series := make(map[string]int, len(result.Names))
+	for _, n := range result.Names {
+		series[n.Name] = len(data.Series)
+		data.Series = append(data.Series, models.TrendSeries{
+			Name:  n.Name,
+			Data:  make([]float64, len(data.Periods)),
+			Total: n.Value,
+		})
+	}
+	for _, point := range result.Points {
+		i, ok := index[point.Period]
+		s, found := series[point.Name]
+		if !ok || !found {
+			continue
+		}
+		data.Series[s].Data[i] += point.Value
+		data.Totals[i] += point.Value
+	}
+	return data
+}
+
+// respondTrend 解析查询参数、查询趋势数据并返回按月对齐的序列
+func respondTrend(c *gin.Context, failMessage string, fetch func(dao.TrendFilter) (*dao.TrendResult, error)) {
+	filter, err := parseTrendQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	result, err := fetch(filter)
+	if err != nil {
+		logger.Errorw(failMessage, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: failMessage,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 只指定了一端时，按默认区间推算的另一端也不能超出范围
+	if result.From != "" && monthsBetween(result.From, result.To) > maxTrendMonths {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   "查询区间不能超过60个月",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    buildTrendData(result),
+	})
+}
@@ -1,14 +1,20 @@
 package controller
 
 import (
+	"errors"
+	"go-web/internal/auth"
 	"go-web/internal/dao"
+	"go-web/internal/middleware"
 	"go-web/internal/models"
 	"go-web/pkg/logger"
 	"go-web/pkg/token"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 
@@ -31,7 +37,7 @@ func RegisterHandler(c *gin.Context) {
 
 	// 检查用户名是否已存在
 	var existingUser models.User
-	if err := db.Where("username = ?", req.Username).First(&existingUser).Error; err == nil {
+	if err := db.Unscoped().Where("username = ?", req.Username).First(&existingUser).Error; err == nil {
 		logger.Warnw("用户名已存在", "username", req.Username)
 		c.JSON(http.StatusConflict, models.ErrorResponse{
 			Code:    http.StatusConflict,
@@ -41,7 +47,7 @@ func RegisterHandler(c *gin.Context) {
 	}
 
 	// 检查邮箱是否已存在
-	if err := db.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
+	if err := db.Unscoped().Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
 		logger.Warnw("邮箱已存在", "email", req.Email)
 		c.JSON(http.StatusConflict, models.ErrorResponse{
 			Code:    http.StatusConflict,
@@ -50,15 +56,9 @@ func RegisterHandler(c *gin.Context) {
 		return
 	}
 
-	// 加密密码
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.PasswordHash), bcrypt.DefaultCost)
-	if err != nil {
-		logger.Errorw("密码加密失败", "error", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "密码加密失败",
-			Error:   err.Error(),
-		})
+	// 校验密码策略并加密密码
+	hashedPassword, ok := preparePassword(c, 0, req.Password, req.PasswordFormat)
+	if !ok {
 		return
 	}
 
@@ -72,11 +72,18 @@ func RegisterHandler(c *gin.Context) {
 	newUser := models.User{
 		Username:     req.Username,
 		Email:        req.Email,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 		// UserType:     userType,
 		Status:       models.UserStatusActive, // 默认激活状态
 	}
 
+	// 开启邮箱验证时，新用户需验证邮箱后才能激活
+	verificationRequired := auth.EmailVerificationEnabled()
+	if verificationRequired {
+		newUser.Status = models.UserStatusInactive
+		newUser.EmailVerificationPending = true
+	}
+
 	if err := db.Create(&newUser).Error; err != nil {
 		logger.Errorw("创建用户失败", "error", err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -91,11 +98,23 @@ func RegisterHandler(c *gin.Context) {
 	newUser.PasswordHash = ""
 
 	logger.Infow("用户注册成功", "user_id", newUser.ID, "username", newUser.Username)
+	recordUserAudit(c, &newUser, models.AuditActionRegister, models.AuditResultSuccess, gin.H{
+		"email_verification": verificationRequired,
+	})
+
+	message := "注册成功"
+	if verificationRequired {
+		// 邮件发送失败时用户可以通过重新发送接口再次获取验证链接
+		if err := sendVerificationEmail(&newUser); err != nil {
+			logger.Errorw("生成邮箱验证链接失败", "error", err, "user_id", newUser.ID)
+		}
+		message = "注册成功，请查收验证邮件完成激活"
+	}
 
 	// 根据接口要求，注册成功响应格式为 HTTP 201
 	c.JSON(http.StatusCreated, models.RegistrationResponse{
 		Code:    http.StatusCreated,
-		Message: "注册成功",
+		Message: message,
 		Data:    &newUser,
 	})
 }
@@ -114,13 +133,42 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
+	// 按 password_format 得到密码的 SHA-256 客户端哈希
+	clientHash, err := auth.LoginClientHash(req.Password, passwordPrehashed(req.PasswordFormat))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "密码格式无效",
+			Error:   reasonPasswordFormat,
+		})
+		return
+	}
+
+	// 检查客户端IP和账户是否因多次失败被锁定
+	if wait, locked := loginLocked(c, req.Username, 0); locked {
+		logger.Warnw("登录尝试被锁定", "username", req.Username, "ip", c.ClientIP())
+		recordUserAudit(c, nil, models.AuditActionLogin, models.AuditResultFailure, gin.H{
+			"identifier": req.Username,
+			"reason":     reasonTooManyAttempts,
+		})
+		respondTooManyAttempts(c, wait)
+		return
+	}
+
 	// 查询用户（支持用户名或邮箱登录）
 	var user models.User
 	db := dao.GetDB()
 
 	result := db.Where("username = ? OR email = ?", req.Username, req.Username).First(&user)
 	if result.Error != nil {
+		// 与密码错误耗时一致，避免通过响应时间判断用户是否存在
+		auth.SimulatePasswordCheck(clientHash)
+		recordLoginFailure(c, req.Username, 0)
 		logger.Warnw("用户不存在", "username", req.Username)
+		recordUserAudit(c, nil, models.AuditActionLogin, models.AuditResultFailure, gin.H{
+			"identifier": req.Username,
+			"reason":     "user_not_found",
+		})
 		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 			Code:    http.StatusUnauthorized,
 			Message: "用户名或密码错误",
@@ -128,9 +176,47 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
-	// 检查用户状态
+	// 同一用户可能用用户名和邮箱交替尝试，按用户ID再检查一次
+	if wait, locked := loginLocked(c, req.Username, user.ID); locked {
+		logger.Warnw("登录尝试被锁定", "user_id", user.ID, "ip", c.ClientIP())
+		recordUserAudit(c, &user, models.AuditActionLogin, models.AuditResultFailure, gin.H{"reason": reasonTooManyAttempts})
+		respondTooManyAttempts(c, wait)
+		return
+	}
+
+	// 验证密码
+	if !auth.CheckPassword(user.PasswordHash, clientHash) {
+		recordLoginFailure(c, req.Username, user.ID)
+		logger.Warnw("密码错误", "user_id", user.ID)
+		recordUserAudit(c, &user, models.AuditActionLogin, models.AuditResultFailure, gin.H{"reason": "invalid_password"})
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "用户名或密码错误",
+		})
+		return
+	}
+
+	clearLoginFailures(req.Username, user.ID)
+
+	// 配置的 bcrypt 代价提高后，登录成功时透明地升级旧哈希
+	if auth.NeedsRehash(user.PasswordHash) {
+		upgradePasswordHash(&user, clientHash)
+	}
+
+	// 密码正确后才检查用户状态，避免未掌握密码的请求探测账户是否存在及其状态
+	if user.EmailVerificationPending {
+		logger.Warnw("用户邮箱未验证", "user_id", user.ID)
+		recordUserAudit(c, &user, models.AuditActionLogin, models.AuditResultFailure, gin.H{"reason": reasonEmailNotVerified})
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "邮箱未验证，请先完成邮箱验证",
+			Error:   reasonEmailNotVerified,
+		})
+		return
+	}
 	if user.Status != models.UserStatusActive {
 		logger.Warnw("用户账户未激活", "user_id", user.ID, "status", user.Status)
+		recordUserAudit(c, &user, models.AuditActionLogin, models.AuditResultFailure, gin.H{"reason": "user_inactive"})
 		c.JSON(http.StatusForbidden, models.ErrorResponse{
 			Code:    http.StatusForbidden,
 			Message: "账户未激活，无法登录",
@@ -138,44 +224,39 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
-	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.PasswordHash)); err != nil {
-		logger.Warnw("密码错误", "user_id", user.ID)
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Code:    http.StatusUnauthorized,
-			Message: "用户名或密码错误",
+	// 管理员要求重置密码的账户需先通过重置邮件设置新密码
+	if user.PasswordResetRequired {
+		logger.Warnw("用户需要重置密码", "user_id", user.ID)
+		recordUserAudit(c, &user, models.AuditActionLogin, models.AuditResultFailure, gin.H{"reason": reasonPasswordResetRequired})
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "账户需要重置密码，请通过邮件中的链接设置新密码",
+			Error:   reasonPasswordResetRequired,
 		})
 		return
 	}
 
-	// 清除密码字段后返回用户信息
-	user.PasswordHash = ""
+	recordUserAudit(c, &user, models.AuditActionLogin, models.AuditResultSuccess, gin.H{"method": "password"})
+	finishLogin(c, &user)
+}
 
-	logger.Infow("用户登录成功", "user_id", user.ID, "username", user.Username)
+func LogoutHandler(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
 
-	// 生成访问令牌
-	accessToken, err := token.GenerateAccessToken(user.ID)
-	if err != nil {
-		logger.Errorw("生成访问令牌失败", "error", err, "user_id", user.ID)
+	// 吊销当前会话，已签发的访问令牌和刷新令牌同时失效
+	if err := auth.RevokeSession(claims.SessionID, models.SessionRevokeLogout); err != nil {
+		logger.Errorw("吊销会话失败", "error", err, "user_id", claims.UserID)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Code:    http.StatusInternalServerError,
-			Message: "生成令牌失败",
+			Message: "登出失败",
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	// 根据接口要求，登录成功响应格式为 HTTP 200
-	response := models.LoginResponse{
-		Code:    http.StatusOK,
-		Message: "登录成功",
-	}
-	response.Data.AccessToken = accessToken
-	response.Data.User = &user
-	c.JSON(http.StatusOK, response)
-}
+	logger.Infow("用户登出成功", "user_id", claims.UserID)
+	recordAudit(c, models.AuditActionLogout, "session", claims.SessionID, models.AuditResultSuccess, nil)
 
-func LogoutHandler(c *gin.Context) {
 	// 根据接口要求，登出成功响应格式为 {code: 200, message: "登出成功", data: {}}
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Code:    http.StatusOK,
@@ -184,28 +265,252 @@ func LogoutHandler(c *gin.Context) {
 	})
 }
 
+// LogoutAllHandler 在所有设备登出（吊销当前用户的全部会话）
+func LogoutAllHandler(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	if err := auth.RevokeUserSessions(claims.UserID, models.SessionRevokeLogoutAll, ""); err != nil {
+		logger.Errorw("吊销用户全部会话失败", "error", err, "user_id", claims.UserID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "登出失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Infow("用户已在所有设备登出", "user_id", claims.UserID)
+	user, _ := middleware.CurrentUser(c)
+	recordUserAudit(c, user, models.AuditActionLogoutAll, models.AuditResultSuccess, nil)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "已在所有设备登出",
+		Data:    gin.H{},
+	})
+}
+
 func RefreshTokenHandler(c *gin.Context) {
-	// 根据接口要求，刚止令牌成功响应格式为 {code: 200, message: "令牌刷新成功", data: {access_token}}
-	// 这里需要实现具体的刷新逻辑
+	var req models.RefreshTokenRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("刷新令牌请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 查找会话：已轮换的旧令牌被再次使用时，整个会话族已被吊销
+	session, err := auth.LookupRefreshSession(req.RefreshToken)
+	if err != nil {
+		respondRefreshError(c, session, err)
+		return
+	}
+
+	// 检查用户状态
+	user, err := dao.GetUserByID(session.UserID)
+	if err != nil || user.Status != models.UserStatusActive {
+		logger.Warnw("刷新令牌对应的用户不可用", "user_id", session.UserID, "error", err)
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "刷新令牌已失效",
+			Error:   reasonRefreshTokenRevoked,
+		})
+		return
+	}
+
+	// 轮换：签发新令牌对，旧刷新令牌立即作废
+	next, tokens, err := newUserSession(c, user.ID, session.FamilyID)
+	if err != nil {
+		logger.Errorw("生成访问令牌失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "生成令牌失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := auth.RotateRefreshSession(session, next); err != nil {
+		if errors.Is(err, auth.ErrRefreshTokenReused) {
+			respondRefreshError(c, session, err)
+			return
+		}
+		logger.Errorw("轮换会话失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "刷新令牌失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Infow("令牌刷新成功", "user_id", user.ID, "session_id", next.ID)
+
+	// 根据接口要求，刷新令牌成功响应格式为 {code: 200, message: "令牌刷新成功", data: {access_token, refresh_token, expires_in}}
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Code:    http.StatusOK,
 		Message: "令牌刷新成功",
-		Data: gin.H{
-			"access_token": "new_access_token_example",
-		},
+		Data:    tokens,
 	})
 }
 
+// respondRefreshError 返回刷新令牌失败响应，重复使用时记录审计事件
+func respondRefreshError(c *gin.Context, session *models.UserSession, err error) {
+	switch {
+	case errors.Is(err, auth.ErrRefreshTokenReused):
+		writeAudit(c, nil, models.AuditActionTokenReuse, "session", session.FamilyID, models.AuditResultFailure, gin.H{
+			"user_id": session.UserID,
+		})
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "刷新令牌已被使用，请重新登录",
+			Error:   reasonRefreshTokenReused,
+		})
+	case errors.Is(err, auth.ErrRefreshTokenRevoked):
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "刷新令牌已失效",
+			Error:   reasonRefreshTokenRevoked,
+		})
+	case errors.Is(err, auth.ErrRefreshTokenExpired):
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "刷新令牌已过期",
+			Error:   reasonRefreshTokenExpired,
+		})
+	default:
+		if err != auth.ErrRefreshTokenInvalid {
+			logger.Errorw("查询会话失败", "error", err)
+		}
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "刷新令牌无效",
+			Error:   reasonRefreshTokenInvalid,
+		})
+	}
+}
+
 func ForgotPasswordHandler(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("忘记密码请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 无论邮箱是否存在都返回相同的响应，防止被用于探测账户
 	// 根据接口要求，忘记密码成功响应格式为 {code: 200, message: "重置邮件已发送，请检查您的邮箱", data: {}}
-	c.JSON(http.StatusOK, models.SuccessResponse{
+	response := models.SuccessResponse{
 		Code:    http.StatusOK,
 		Message: "重置邮件已发送，请检查您的邮箱",
 		Data:    gin.H{},
-	})
+	}
+
+	var user models.User
+	db := dao.GetDB()
+	if err := db.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Errorw("查询用户失败", "error", err)
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	// 未激活的账户不发送重置邮件
+	if user.Status != models.UserStatusActive {
+		logger.Warnw("未激活账户申请重置密码", "user_id", user.ID)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if err := issuePasswordReset(c, &user); err != nil {
+		logger.Errorw("发送密码重置邮件失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	logger.Infow("已发送密码重置邮件", "user_id", user.ID)
+	recordUserAudit(c, &user, models.AuditActionPasswordForgot, models.AuditResultSuccess, nil)
+
+	c.JSON(http.StatusOK, response)
 }
 
 func ResetPasswordHandler(c *gin.Context) {
+	var req models.ResetPasswordRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("重置密码请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	invalidToken := models.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: "重置链接无效或已过期",
+		Error:   reasonResetTokenInvalid,
+	}
+
+	// 令牌必须存在、未使用且未过期
+	resetToken, err := dao.GetPasswordResetToken(token.HashToken(req.Token))
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Errorw("查询重置令牌失败", "error", err)
+		}
+		c.JSON(http.StatusBadRequest, invalidToken)
+		return
+	}
+	if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		writeAudit(c, nil, models.AuditActionPasswordReset, "user", strconv.FormatUint(uint64(resetToken.UserID), 10),
+			models.AuditResultFailure, gin.H{"reason": reasonResetTokenInvalid})
+		c.JSON(http.StatusBadRequest, invalidToken)
+		return
+	}
+
+	hashedPassword, ok := preparePassword(c, resetToken.UserID, req.NewPassword, req.PasswordFormat)
+	if !ok {
+		return
+	}
+
+	if err := dao.ResetPassword(resetToken, hashedPassword, auth.PasswordHistoryKeep()); err != nil {
+		if errors.Is(err, dao.ErrResetTokenUsed) {
+			writeAudit(c, nil, models.AuditActionPasswordReset, "user", strconv.FormatUint(uint64(resetToken.UserID), 10),
+				models.AuditResultFailure, gin.H{"reason": "reset_token_used"})
+			c.JSON(http.StatusBadRequest, invalidToken)
+			return
+		}
+		logger.Errorw("重置密码失败", "error", err, "user_id", resetToken.UserID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "重置密码失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 密码已重置，吊销该用户所有已登录的会话
+	if err := auth.RevokeUserSessions(resetToken.UserID, models.SessionRevokePasswordReset, ""); err != nil {
+		logger.Errorw("吊销用户会话失败", "error", err, "user_id", resetToken.UserID)
+	}
+
+	logger.Infow("密码重置成功", "user_id", resetToken.UserID)
+	writeAudit(c, nil, models.AuditActionPasswordReset, "user", strconv.FormatUint(uint64(resetToken.UserID), 10),
+		models.AuditResultSuccess, nil)
+
 	// 根据接口要求，重置密码成功响应格式为 {code: 200, message: "密码重置成功", data: {}}
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Code:    http.StatusOK,
@@ -216,40 +521,190 @@ func ResetPasswordHandler(c *gin.Context) {
 
 // 用户相关处理器
 func GetProfileHandler(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+
 	// 根据接口要求，获取用户资料成功响应格式为 {code: 200, message: "获取成功", data: {用户信息}}
-	// 这里需要实现具体的获取用户资料逻辑
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Code:    http.StatusOK,
 		Message: "获取成功",
-		Data: gin.H{
-			"id":         1,
-			"username":   "example_user",
-			"email":      "user@example.com",
-			"user_type":  "app",
-			"status":     "active",
-			"created_at": "2024-01-15T10:30:00Z",
-			"updated_at": "2024-01-15T10:30:00Z",
-		},
+		Data:    user,
 	})
 }
 
 func UpdateProfileHandler(c *gin.Context) {
+	var req models.UpdateProfileRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("更新资料请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	db := dao.GetDB()
+
+	updates := map[string]interface{}{}
+	var existingUser models.User
+
+	// 检查用户名是否被其他用户占用
+	if req.Username != "" && req.Username != user.Username {
+		if err := db.Unscoped().Where("username = ? AND id <> ?", req.Username, user.ID).First(&existingUser).Error; err == nil {
+			logger.Warnw("用户名已存在", "username", req.Username)
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "用户名已存在",
+			})
+			return
+		}
+		updates["username"] = req.Username
+	}
+
+	// 检查邮箱是否被其他用户占用
+	if req.Email != "" && req.Email != user.Email {
+		if err := db.Unscoped().Where("email = ? AND id <> ?", req.Email, user.ID).First(&existingUser).Error; err == nil {
+			logger.Warnw("邮箱已存在", "email", req.Email)
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "邮箱已被注册",
+			})
+			return
+		}
+		// 新邮箱需重新验证，旧的验证时间和发往旧邮箱的验证链接随之失效
+		updates["email"] = req.Email
+		updates["email_verified_at"] = nil
+	}
+
+	if len(updates) > 0 {
+		if err := db.Model(user).Updates(updates).Error; err != nil {
+			logger.Errorw("更新用户资料失败", "error", err, "user_id", user.ID)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "更新用户资料失败",
+				Error:   err.Error(),
+			})
+			return
+		}
+		logger.Infow("用户资料更新成功", "user_id", user.ID, "fields", len(updates))
+
+		changed := make([]string, 0, len(updates))
+		for field := range updates {
+			if field != "email_verified_at" {
+				changed = append(changed, field)
+			}
+		}
+		sort.Strings(changed)
+		recordUserAudit(c, user, models.AuditActionProfileUpdate, models.AuditResultSuccess, gin.H{"fields": changed})
+	}
+
+	message := "更新成功"
+	if _, ok := updates["email"]; ok {
+		user.EmailVerifiedAt = nil
+		// 邮件发送失败不影响资料更新，新邮箱保持未验证状态
+		if auth.EmailVerificationEnabled() {
+			if err := sendEmailChangeVerification(user); err != nil {
+				logger.Errorw("生成邮箱验证链接失败", "error", err, "user_id", user.ID)
+			} else {
+				message = "更新成功，请查收新邮箱中的验证邮件"
+			}
+		}
+	}
+
 	// 根据接口要求，更新用户资料成功响应格式为 {code: 200, message: "更新成功", data: {更新后的用户信息}}
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Code:    http.StatusOK,
-		Message: "更新成功",
-		Data: gin.H{
-			"id":         1,
-			"username":   "updated_user",
-			"email":      "updated@example.com",
-			"user_type":  "app",
-			"status":     "active",
-			"updated_at": "2024-01-15T10:30:00Z",
-		},
+		Message: message,
+		Data:    user,
 	})
 }
 
 func ChangePasswordHandler(c *gin.Context) {
+	var req models.ChangePasswordRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("修改密码请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	currentUser, _ := middleware.CurrentUser(c)
+	claims, _ := middleware.CurrentClaims(c)
+
+	// 验证旧密码（管理员修改他人密码时验证的是管理员自己的密码）
+	oldHash, err := auth.LoginClientHash(req.OldPassword, passwordPrehashed(req.PasswordFormat))
+	if err != nil || !auth.CheckPassword(currentUser.PasswordHash, oldHash) {
+		logger.Warnw("修改密码时旧密码错误", "user_id", currentUser.ID)
+		recordUserAudit(c, currentUser, models.AuditActionPasswordChange, models.AuditResultFailure, gin.H{"reason": "invalid_password"})
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "原密码错误",
+		})
+		return
+	}
+
+	// 确定要修改密码的用户，修改他人密码仅限系统用户
+	targetUser := currentUser
+	if req.TargetUsername != "" && req.TargetUsername != currentUser.Username {
+		if currentUser.UserType != models.UserTypeSystem {
+			logger.Warnw("非系统用户尝试修改他人密码", "user_id", currentUser.ID, "target", req.TargetUsername)
+			recordAudit(c, models.AuditActionPasswordChange, "user", "", models.AuditResultFailure, gin.H{
+				"target_username": req.TargetUsername,
+				"reason":          "permission_denied",
+			})
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "无权修改其他用户的密码",
+			})
+			return
+		}
+
+		var user models.User
+		if err := dao.GetDB().Where("username = ?", req.TargetUsername).First(&user).Error; err != nil {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "目标用户不存在",
+			})
+			return
+		}
+		targetUser = &user
+	}
+
+	hashedPassword, ok := preparePassword(c, targetUser.ID, req.NewPassword, req.PasswordFormat)
+	if !ok {
+		return
+	}
+
+	if err := dao.UpdateUserPassword(targetUser.ID, hashedPassword, auth.PasswordHistoryKeep()); err != nil {
+		logger.Errorw("更新密码失败", "error", err, "user_id", targetUser.ID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "修改密码失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 修改自己的密码时保留当前会话，其余会话全部吊销；修改他人密码时吊销其全部会话
+	keepSession := ""
+	if targetUser.ID == currentUser.ID {
+		keepSession = claims.SessionID
+	}
+	if err := auth.RevokeUserSessions(targetUser.ID, models.SessionRevokePasswordChange, keepSession); err != nil {
+		logger.Errorw("吊销用户会话失败", "error", err, "user_id", targetUser.ID)
+	}
+
+	logger.Infow("密码修改成功", "user_id", targetUser.ID, "operator_id", currentUser.ID)
+	recordAudit(c, models.AuditActionPasswordChange, "user", userTargetID(targetUser), models.AuditResultSuccess, nil)
+
 	// 根据接口要求，修改密码成功响应格式为 {code: 200, message: "密码修改成功", data: {}}
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Code:    http.StatusOK,
@@ -282,6 +737,12 @@ func CheckUsernameHandler(c *gin.Context) {
 		return
 	}
 
+	// 按IP限制检查频率，防止批量枚举账户
+	if wait, locked := auth.LookupLimiter.Fail(auth.LookupKey(c.ClientIP())); locked {
+		respondTooManyAttempts(c, wait)
+		return
+	}
+
 	db := dao.GetDB()
 	var user models.User
 	// 检查用户名是否已存在
@@ -321,6 +782,12 @@ func CheckEmailHandler(c *gin.Context) {
 		return
 	}
 
+	// 按IP限制检查频率，防止批量枚举账户
+	if wait, locked := auth.LookupLimiter.Fail(auth.LookupKey(c.ClientIP())); locked {
+		respondTooManyAttempts(c, wait)
+		return
+	}
+
 	db := dao.GetDB()
 	var user models.User
 	// 检查邮箱是否已存在
@@ -346,38 +813,157 @@ func CheckEmailHandler(c *gin.Context) {
 	})
 }
 
+// userSortFields 用户列表允许排序的字段
+var userSortFields = map[string]string{
+	"id":         "id",
+	"username":   "username",
+	"email":      "email",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
 // 获取用户列表（仅管理员）
 func GetUsersListHandler(c *gin.Context) {
+	var req models.UserListQuery
+
+	// 绑定并验证查询参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Errorw("用户列表查询参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	filter := dao.UserListFilter{
+		UserType: req.UserType,
+		Status:   req.Status,
+		Search:   strings.TrimSpace(req.Search),
+	}
+	// 日期格式已由 binding 校验，截止日期包含当天
+	if req.CreatedFrom != "" {
+		from, _ := time.ParseInLocation(time.DateOnly, req.CreatedFrom, time.Local)
+		filter.CreatedFrom = &from
+	}
+	if req.CreatedTo != "" {
+		to, _ := time.ParseInLocation(time.DateOnly, req.CreatedTo, time.Local)
+		to = to.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+
+	if req.Mode == "cursor" {
+		listUsersByCursor(c, &req, filter)
+		return
+	}
+
+	column, desc, err := parseSort(req.Sort, userSortFields, "created_at:desc")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 追加主键排序，保证排序稳定
+	orderBy := orderClause(column, desc) + ", " + orderClause("id", desc)
+	users, total, err := dao.ListUsers(filter, orderBy, (req.Page-1)*req.Limit, req.Limit)
+	if err != nil {
+		logger.Errorw("获取用户列表失败", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "获取用户列表失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	// 根据接口要求，获取用户列表成功响应格式为 {code: 200, message: "获取成功", data: [用户信息数组], pagination: {...}}
-	// 这里需要实现具体的获取用户列表逻辑
-	c.JSON(http.StatusOK, gin.H{
-		"code":    http.StatusOK,
-		"message": "获取成功",
-		"data": []gin.H{
-			{
-				"id":         1,
-				"username":   "john_doe",
-				"email":      "john@example.com",
-				"user_type":  "app",
-				"status":     "active",
-				"created_at": "2024-01-15T10:30:00Z",
-				"updated_at": "2024-01-15T10:30:00Z",
-			},
-			{
-				"id":         2,
-				"username":   "admin_user",
-				"email":      "admin@example.com",
-				"user_type":  "system",
-				"status":     "active",
-				"created_at": "2024-01-10T10:30:00Z",
-				"updated_at": "2024-01-15T10:30:00Z",
-			},
-		},
-		"pagination": gin.H{
-			"total": 50,
-			"page":  1,
-			"limit": 20,
-			"pages": 3,
+	c.JSON(http.StatusOK, models.PagedResponse{
+		Code:    http.StatusOK,
+		Message: "获取成功",
+		Data:    toUserListItems(users),
+		Pagination: models.PaginationResponse{
+			Total: int(total),
+			Page:  req.Page,
+			Limit: req.Limit,
+			Pages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
 		},
 	})
 }
+
+// listUsersByCursor 游标分页获取用户列表，只支持按 id 排序，不统计总数
+func listUsersByCursor(c *gin.Context, req *models.UserListQuery, filter dao.UserListFilter) {
+	_, desc, err := parseSort(req.Sort, map[string]string{"id": "id"}, "id:desc")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "游标分页仅支持按 id 排序",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var afterID uint
+	if req.Cursor != "" {
+		id, err := decodeCursor(req.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "游标无效",
+				Error:   err.Error(),
+			})
+			return
+		}
+		afterID = id
+	}
+
+	// 多取一条用于判断是否还有下一页
+	users, err := dao.ListUsersByCursor(filter, afterID, desc, req.Limit+1)
+	if err != nil {
+		logger.Errorw("获取用户列表失败", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "获取用户列表失败",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	pagination := models.CursorPaginationResponse{Limit: req.Limit}
+	if len(users) > req.Limit {
+		users = users[:req.Limit]
+		pagination.HasMore = true
+		pagination.NextCursor = encodeCursor(users[len(users)-1].ID)
+	}
+
+	c.JSON(http.StatusOK, models.PagedResponse{
+		Code:       http.StatusOK,
+		Message:    "获取成功",
+		Data:       toUserListItems(users),
+		Pagination: pagination,
+	})
+}
+
+// toUserListItems 为用户列表附加登录锁定状态
+func toUserListItems(users []models.User) []models.UserListItem {
+	items := make([]models.UserListItem, 0, len(users))
+	for _, user := range users {
+		item := models.UserListItem{User: user}
+		if until, locked := auth.AccountLimiter.LockedUntil(auth.UserKey(user.ID)); locked {
+			item.Locked = true
+			item.LockedUntil = &until
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-web/internal/auth"
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// VerifyEmailHandler 确认邮箱验证链接并激活账户
+func VerifyEmailHandler(c *gin.Context) {
+	var req models.VerifyEmailRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("邮箱验证请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	userID, err := auth.VerifyEmail(req.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrVerificationTokenExpired):
+			writeAudit(c, nil, models.AuditActionEmailVerify, "user", "", models.AuditResultFailure, gin.H{"reason": reasonVerifyTokenExpired})
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "验证链接已过期，请重新发送验证邮件",
+				Error:   reasonVerifyTokenExpired,
+			})
+		case errors.Is(err, auth.ErrVerificationTokenInvalid):
+			writeAudit(c, nil, models.AuditActionEmailVerify, "user", "", models.AuditResultFailure, gin.H{"reason": reasonVerifyTokenInvalid})
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "验证链接无效或已使用",
+				Error:   reasonVerifyTokenInvalid,
+			})
+		default:
+			logger.Errorw("邮箱验证失败", "error", err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "邮箱验证失败",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	logger.Infow("用户邮箱验证成功", "user_id", userID)
+	writeAudit(c, nil, models.AuditActionEmailVerify, "user", strconv.FormatUint(uint64(userID), 10), models.AuditResultSuccess, nil)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "邮箱验证成功，请登录",
+		Data:    gin.H{},
+	})
+}
+
+// ResendVerificationHandler 重新发送验证邮件
+// 按邮箱和IP限制频率；无论邮箱是否存在、是否需要验证都返回相同的响应，防止被用于探测账户
+func ResendVerificationHandler(c *gin.Context) {
+	var req models.ResendVerificationRequest
+
+	// 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorw("重新发送验证邮件请求参数验证失败", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "请求参数错误",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if !auth.EmailVerificationEnabled() {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "未开启邮箱验证",
+		})
+		return
+	}
+
+	if wait, locked := auth.VerificationLimiter.Fail(auth.VerificationIPKey(c.ClientIP())); locked {
+		respondTooManyAttempts(c, wait)
+		return
+	}
+	if wait, locked := auth.VerificationLimiter.Fail(auth.VerificationEmailKey(req.Email)); locked {
+		respondTooManyAttempts(c, wait)
+		return
+	}
+
+	response := models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "如果该邮箱正在等待验证，验证邮件已重新发送",
+		Data:    gin.H{},
+	}
+
+	var user models.User
+	if err := dao.GetDB().Where("email = ?", req.Email).First(&user).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Errorw("查询用户失败", "error", err)
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if !user.EmailVerificationPending {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if err := sendVerificationEmail(&user); err != nil {
+		logger.Errorw("生成邮箱验证链接失败", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	logger.Infow("已重新发送验证邮件", "user_id", user.ID)
+
+	c.JSON(http.StatusOK, response)
+}
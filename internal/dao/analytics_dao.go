@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"gorm.io/gorm"
+)
+
+// AnalyticsFilter 分析数据通用过滤条件，作用于各汇总表的名称列和数值列
+type AnalyticsFilter struct {
+	Min      *float64 // 数值下限（含）
+	Max      *float64 // 数值上限（含）
+	Contains string   // 名称模糊匹配
+}
+
+// apply 将过滤条件应用到查询
+func (f AnalyticsFilter) apply(db *gorm.DB, nameColumn, valueColumn string) *gorm.DB {
+	if f.Min != nil {
+		db = db.Where(valueColumn+" >= ?", *f.Min)
+	}
+	if f.Max != nil {
+		db = db.Where(valueColumn+" <= ?", *f.Max)
+	}
+	if f.Contains != "" {
+		db = db.Where(nameColumn+" LIKE ?", "%"+escapeLike(f.Contains)+"%")
+	}
+	return db
+}
+
+// listAnalytics 按过滤条件和排序分页查询分析数据表，同时返回满足条件的总数
+// limit 为0时返回全部数据；列名由各数据表的 dao 函数传入，不来自请求参数
+func listAnalytics[T any](filter AnalyticsFilter, nameColumn, valueColumn, orderBy string, offset, limit int) ([]T, int64, error) {
+	var rows []T
+	var total int64
+
+	query := filter.apply(DB.Model(new(T)), nameColumn, valueColumn)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order(orderBy)
+	if limit > 0 {
+		query = query.Offset(offset).Limit(limit)
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rows, total, nil
+}
+
+// AnalyticsValue 分析数据项的名称和数值
+type AnalyticsValue struct {
+	Name  string
+	Value float64
+}
+
+// listAnalyticsValues 获取满足过滤条件的全部名称和数值，按数值降序、名称升序排列
+// 用于计算占比、排名和汇总统计，只读取两列
+func listAnalyticsValues[T any](filter AnalyticsFilter, nameColumn, valueColumn string) ([]AnalyticsValue, error) {
+	var values []AnalyticsValue
+
+	result := filter.apply(DB.Model(new(T)), nameColumn, valueColumn).
+		Select(nameColumn + " AS name, " + valueColumn + " AS value").
+		Order(valueColumn + " DESC, " + nameColumn + " ASC").
+		Scan(&values)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return values, nil
+}
@@ -0,0 +1,53 @@
+package dao
+
+import (
+	"time"
+
+	"go-web/internal/models"
+)
+
+// CreateAPIKey 创建 API 密钥
+func CreateAPIKey(key *models.APIKey) error {
+	return DB.Create(key).Error
+}
+
+// GetAPIKeyByHash 根据密钥哈希获取 API 密钥
+func GetAPIKeyByHash(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+
+	result := DB.Where("key_hash = ?", keyHash).First(&key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &key, nil
+}
+
+// ListUserAPIKeys 获取用户的全部 API 密钥（含已吊销、已过期），按创建时间倒序
+func ListUserAPIKeys(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := DB.Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// RevokeAPIKey 吊销用户的 API 密钥，密钥不存在或已吊销时返回 false
+func RevokeAPIKey(userID, keyID uint) (bool, error) {
+	result := DB.Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// TouchAPIKey 记录 API 密钥的使用时间和IP；距上次记录不足 interval 时跳过，避免每个请求都写库
+func TouchAPIKey(keyID uint, ip string, interval time.Duration) error {
+	now := time.Now()
+	return DB.Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", keyID, now.Add(-interval)).
+		Updates(map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": ip,
+		}).Error
+}
@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"go-web/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CreateAuditEvent 写入审计事件（审计表只追加，不提供修改和删除）
+func CreateAuditEvent(event *models.AuditEvent) error {
+	return DB.Create(event).Error
+}
+
+// AuditEventFilter 审计事件过滤条件
+type AuditEventFilter struct {
+	ActorID    *uint
+	Actor      string // 操作者用户名，精确匹配
+	Action     string // 以 . 或 * 结尾时按前缀匹配，如 auth. 匹配全部认证事件
+	TargetType string
+	TargetID   string
+	Result     string
+	IP         string
+	From       *time.Time // 含
+	To         *time.Time // 不含
+}
+
+// apply 将过滤条件应用到查询
+func (f AuditEventFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.ActorID != nil {
+		db = db.Where("actor_id = ?", *f.ActorID)
+	}
+	if f.Actor != "" {
+		db = db.Where("actor_username = ?", f.Actor)
+	}
+	if f.Action != "" {
+		if strings.HasSuffix(f.Action, ".") || strings.HasSuffix(f.Action, "*") {
+			prefix := strings.TrimSuffix(f.Action, "*")
+			db = db.Where("action LIKE ?", escapeLike(prefix)+"%")
+		} else {
+			db = db.Where("action = ?", f.Action)
+		}
+	}
+	if f.TargetType != "" {
+		db = db.Where("target_type = ?", f.TargetType)
+	}
+	if f.TargetID != "" {
+		db = db.Where("target_id = ?", f.TargetID)
+	}
+	if f.Result != "" {
+		db = db.Where("result = ?", f.Result)
+	}
+	if f.IP != "" {
+		db = db.Where("ip_address = ?", f.IP)
+	}
+	if f.From != nil {
+		db = db.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		db = db.Where("created_at < ?", *f.To)
+	}
+	return db
+}
+
+// ListAuditEvents 按时间倒序分页获取审计事件，同时返回满足条件的总数
+func ListAuditEvents(filter AuditEventFilter, offset, limit int) ([]models.AuditEvent, int64, error) {
+	var events []models.AuditEvent
+	var total int64
+
+	query := filter.apply(DB.Model(&models.AuditEvent{}))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&events)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return events, total, nil
+}
+
+// EachAuditEvent 按主键倒序分批遍历审计事件，最多 max 条，用于导出
+// fn 返回错误时停止遍历并返回该错误
+func EachAuditEvent(filter AuditEventFilter, batchSize, max int, fn func([]models.AuditEvent) error) error {
+	var beforeID uint
+	remaining := max
+
+	for remaining > 0 {
+		size := batchSize
+		if size > remaining {
+			size = remaining
+		}
+
+		var events []models.AuditEvent
+		query := filter.apply(DB.Model(&models.AuditEvent{}))
+		if beforeID > 0 {
+			query = query.Where("id < ?", beforeID)
+		}
+		if err := query.Order("id DESC").Limit(size).Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		if err := fn(events); err != nil {
+			return err
+		}
+
+		remaining -= len(events)
+		beforeID = events[len(events)-1].ID
+		if len(events) < size {
+			return nil
+		}
+	}
+
+	return nil
+}
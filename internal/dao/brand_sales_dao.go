@@ -4,41 +4,22 @@ import (
 	"go-web/internal/models"
 )
 
-// GetBrandSales 获取所有品牌销售数据
-func GetBrandSales() ([]models.BrandSales, error) {
-	var brandSales []models.BrandSales
-
-	// 查询所有品牌销售数据，按销售额降序排列
-	result := DB.Order("total_sales DESC").Find(&brandSales)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	return brandSales, nil
+// ListBrandSales 按条件分页获取品牌销售数据，同时返回满足条件的总数
+func ListBrandSales(filter AnalyticsFilter, orderBy string, offset, limit int) ([]models.BrandSales, int64, error) {
+	return listAnalytics[models.BrandSales](filter, "brand_name", "total_sales", orderBy, offset, limit)
 }
 
-// GetTopBrandSales 获取前N名品牌销售数据
-func GetTopBrandSales(limit int) ([]models.BrandSales, error) {
-	var brandSales []models.BrandSales
-
-	// 查询前N名品牌销售数据，按销售额降序排列
-	result := DB.Order("total_sales DESC").Limit(limit).Find(&brandSales)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	return brandSales, nil
+// BrandSalesValues 获取满足条件的全部品牌及销售额，用于计算占比和汇总统计
+func BrandSalesValues(filter AnalyticsFilter) ([]AnalyticsValue, error) {
+	return listAnalyticsValues[models.BrandSales](filter, "brand_name", "total_sales")
 }
 
-// GetLastBrandSales 获取后N名品牌销售数据
-func GetLastBrandSales(limit int) ([]models.BrandSales, error) {
-	var brandSales []models.BrandSales
-
-	// 查询后N名品牌销售数据，按销售额升序排列
-	result := DB.Order("total_sales ASC").Limit(limit).Find(&brandSales)
-	if result.Error != nil {
-		return nil, result.Error
-	}
+// BrandSalesTrend 获取品牌月度销售趋势
+func BrandSalesTrend(filter TrendFilter) (*TrendResult, error) {
+	return listTrend[models.BrandSalesMonthly](filter, "brand_name", "total_sales")
+}
 
-	return brandSales, nil
+// BrandSalesByPeriod 获取某个年月的全部品牌销售额，period 为空时取最新年月
+func BrandSalesByPeriod(period string) (string, []AnalyticsValue, error) {
+	return listPeriodValues[models.BrandSalesMonthly](period, "brand_name", "total_sales")
 }
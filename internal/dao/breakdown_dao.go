@@ -0,0 +1,41 @@
+package dao
+
+import (
+	"go-web/internal/models"
+)
+
+// BreakdownFilter 交叉分析查询条件
+type BreakdownFilter struct {
+	From string // 起始年月（含），为空时不限制
+	To   string // 截止年月（含），为空时不限制
+}
+
+// BreakdownCell 交叉分析的单元格
+type BreakdownCell struct {
+	Row   string
+	Col   string
+	Value float64
+}
+
+// CarSalesBreakdown 按行、列两个维度汇总销量明细
+// rowColumn、colColumn 和 measure 必须来自调用方的白名单，不能直接使用请求参数
+func CarSalesBreakdown(filter BreakdownFilter, rowColumn, colColumn, measure string) ([]BreakdownCell, error) {
+	var cells []BreakdownCell
+
+	db := DB.Model(&models.CarSalesDetail{})
+	if filter.From != "" {
+		db = db.Where("period >= ?", filter.From)
+	}
+	if filter.To != "" {
+		db = db.Where("period <= ?", filter.To)
+	}
+
+	result := db.Select(rowColumn + " AS `row`, " + colColumn + " AS col, " + measure + " AS value").
+		Group(rowColumn + ", " + colColumn).
+		Scan(&cells)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return cells, nil
+}
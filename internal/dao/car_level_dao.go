@@ -4,41 +4,22 @@ import (
 	"go-web/internal/models"
 )
 
-// GetCarLevelDistribution 获取所有汽车级别分布数据
-func GetCarLevelDistribution() ([]models.CarLevelDistribution, error) {
-	var carLevels []models.CarLevelDistribution
-
-	// 查询所有汽车级别分布数据，按汽车数量降序排列
-	result := DB.Order("car_count DESC").Find(&carLevels)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	return carLevels, nil
+// ListCarLevelDistribution 按条件分页获取汽车级别分布数据，同时返回满足条件的总数
+func ListCarLevelDistribution(filter AnalyticsFilter, orderBy string, offset, limit int) ([]models.CarLevelDistribution, int64, error) {
+	return listAnalytics[models.CarLevelDistribution](filter, "car_level", "car_count", orderBy, offset, limit)
 }
 
-// GetTopCarLevels 获取前N名汽车级别分布数据
-func GetTopCarLevels(limit int) ([]models.CarLevelDistribution, error) {
-	var carLevels []models.CarLevelDistribution
-
-	// 查询前N名汽车级别分布数据，按汽车数量降序排列
-	result := DB.Order("car_count DESC").Limit(limit).Find(&carLevels)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	return carLevels, nil
+// CarLevelDistributionValues 获取满足条件的全部汽车级别及数量，用于计算占比和汇总统计
+func CarLevelDistributionValues(filter AnalyticsFilter) ([]AnalyticsValue, error) {
+	return listAnalyticsValues[models.CarLevelDistribution](filter, "car_level", "car_count")
 }
 
-// GetLastCarLevels 获取后N名汽车级别分布数据
-func GetLastCarLevels(limit int) ([]models.CarLevelDistribution, error) {
-	var carLevels []models.CarLevelDistribution
-
-	// 查询后N名汽车级别分布数据，按汽车数量升序排列
-	result := DB.Order("car_count ASC").Limit(limit).Find(&carLevels)
-	if result.Error != nil {
-		return nil, result.Error
-	}
+// CarLevelTrend 获取汽车级别月度分布趋势
+func CarLevelTrend(filter TrendFilter) (*TrendResult, error) {
+	return listTrend[models.CarLevelMonthly](filter, "car_level", "car_count")
+}
 
-	return carLevels, nil
+// CarLevelByPeriod 获取某个年月的全部汽车级别数量，period 为空时取最新年月
+func CarLevelByPeriod(period string) (string, []AnalyticsValue, error) {
+	return listPeriodValues[models.CarLevelMonthly](period, "car_level", "car_count")
 }
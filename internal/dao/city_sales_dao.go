@@ -4,28 +4,22 @@ import (
 	"go-web/internal/models"
 )
 
-// GetCitySales 获取城市销售数据
-func GetCitySales() ([]models.CitySales, error) {
-	var citySales []models.CitySales
-
-	// 查询所有城市销售数据，按销售数量降序排列
-	result := DB.Order("sales DESC").Find(&citySales)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	return citySales, nil
+// ListCitySales 按条件分页获取城市销售数据，同时返回满足条件的总数
+func ListCitySales(filter AnalyticsFilter, orderBy string, offset, limit int) ([]models.CitySales, int64, error) {
+	return listAnalytics[models.CitySales](filter, "city", "sales", orderBy, offset, limit)
 }
 
-// GetTopCitySales 获取前N名城市销售数据
-func GetTopCitySales(limit int) ([]models.CitySales, error) {
-	var citySales []models.CitySales
+// CitySalesValues 获取满足条件的全部城市及销量，用于计算占比和汇总统计
+func CitySalesValues(filter AnalyticsFilter) ([]AnalyticsValue, error) {
+	return listAnalyticsValues[models.CitySales](filter, "city", "sales")
+}
 
-	// 查询前N名城市销售数据，按销售数量降序排列
-	result := DB.Order("sales DESC").Limit(limit).Find(&citySales)
-	if result.Error != nil {
-		return nil, result.Error
-	}
+// CitySalesTrend 获取城市月度销售趋势
+func CitySalesTrend(filter TrendFilter) (*TrendResult, error) {
+	return listTrend[models.CitySalesMonthly](filter, "city", "sales")
+}
 
-	return citySales, nil
+// CitySalesByPeriod 获取某个年月的全部城市销量，period 为空时取最新年月
+func CitySalesByPeriod(period string) (string, []AnalyticsValue, error) {
+	return listPeriodValues[models.CitySalesMonthly](period, "city", "sales")
 }
@@ -0,0 +1,60 @@
+package dao
+
+import (
+	"go-web/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DrillCondition 下钻路径上某一层级的取值
+type DrillCondition struct {
+	Column string // 层级对应的列表达式
+	Value  string
+}
+
+// drilldownScope 销量明细关联城市参考表，并按时间区间和下钻路径过滤
+// 列表达式必须来自调用方的白名单
+func drilldownScope(filter BreakdownFilter, path []DrillCondition) *gorm.DB {
+	db := DB.Table(models.CarSalesDetail{}.TableName() + " AS d").
+		Joins("LEFT JOIN " + models.CityRegion{}.TableName() + " AS r ON r.city = d.city")
+	if filter.From != "" {
+		db = db.Where("d.period >= ?", filter.From)
+	}
+	if filter.To != "" {
+		db = db.Where("d.period <= ?", filter.To)
+	}
+	for _, cond := range path {
+		db = db.Where(cond.Column+" = ?", cond.Value)
+	}
+	return db
+}
+
+// CarSalesDrilldown 汇总下钻路径下各子节点的销量，按销量降序排列
+func CarSalesDrilldown(filter BreakdownFilter, path []DrillCondition, childColumn string) ([]AnalyticsValue, error) {
+	var values []AnalyticsValue
+
+	result := drilldownScope(filter, path).
+		Select(childColumn + " AS name, SUM(d.sales) AS value").
+		Group(childColumn).
+		Order("value DESC, name ASC").
+		Scan(&values)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return values, nil
+}
+
+// CarSalesRollup 汇总下钻路径下的总销量
+func CarSalesRollup(filter BreakdownFilter, path []DrillCondition) (float64, error) {
+	var total float64
+
+	result := drilldownScope(filter, path).
+		Select("COALESCE(SUM(d.sales), 0)").
+		Scan(&total)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return total, nil
+}
@@ -4,15 +4,54 @@ import (
     "go-web/internal/models"
 )
 
-// GetEnergyDistribution 获取能源类型分布数据
-func GetEnergyDistribution() ([]models.EnergyType, error) {
-    var energyList []models.EnergyType
-    
-    // 查询所有数据
-    result := DB.Find(&energyList)
+// ListEnergyDistribution 按条件分页获取能源类型分布数据，同时返回满足条件的总数
+func ListEnergyDistribution(filter AnalyticsFilter, orderBy string, offset, limit int) ([]models.EnergyType, int64, error) {
+    return listAnalytics[models.EnergyType](filter, "energy_type", "car_count", orderBy, offset, limit)
+}
+
+// EnergyDistributionValues 获取满足条件的全部能源类型及数量，用于计算占比和汇总统计
+func EnergyDistributionValues(filter AnalyticsFilter) ([]AnalyticsValue, error) {
+    return listAnalyticsValues[models.EnergyType](filter, "energy_type", "car_count")
+}
+
+// EnergyTrend 获取能源类型月度分布趋势
+func EnergyTrend(filter TrendFilter) (*TrendResult, error) {
+    return listTrend[models.EnergyTypeMonthly](filter, "energy_type", "car_count")
+}
+
+// EnergyByPeriod 获取某个年月的全部能源类型数量，period 为空时取最新年月
+func EnergyByPeriod(period string) (string, []AnalyticsValue, error) {
+    return listPeriodValues[models.EnergyTypeMonthly](period, "energy_type", "car_count")
+}
+
+// EnergyBreakdownRow 某个维度取值下某种能源类型的数量
+type EnergyBreakdownRow struct {
+    Name       string
+    EnergyType string
+    Value      float64
+}
+
+// listEnergyBreakdown 按维度和能源类型汇总数量
+func listEnergyBreakdown[T any](dimensionColumn string) ([]EnergyBreakdownRow, error) {
+    var rows []EnergyBreakdownRow
+
+    result := DB.Model(new(T)).
+        Select(dimensionColumn + " AS name, energy_type, SUM(car_count) AS value").
+        Group(dimensionColumn + ", energy_type").
+        Scan(&rows)
     if result.Error != nil {
         return nil, result.Error
     }
-    
-    return energyList, nil
-}
\ No newline at end of file
+
+    return rows, nil
+}
+
+// CityEnergyBreakdown 获取各城市的能源类型分布
+func CityEnergyBreakdown() ([]EnergyBreakdownRow, error) {
+    return listEnergyBreakdown[models.CityEnergyDistribution]("city")
+}
+
+// CarLevelEnergyBreakdown 获取各汽车级别的能源类型分布
+func CarLevelEnergyBreakdown() ([]EnergyBreakdownRow, error) {
+    return listEnergyBreakdown[models.CarLevelEnergyDistribution]("car_level")
+}
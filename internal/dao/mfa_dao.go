@@ -0,0 +1,129 @@
+package dao
+
+import (
+	"time"
+
+	"go-web/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetUserMFA 获取用户二次验证配置
+func GetUserMFA(userID uint) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+
+	result := DB.Where("user_id = ?", userID).First(&mfa)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &mfa, nil
+}
+
+// SaveUserMFA 创建或覆盖用户二次验证配置
+func SaveUserMFA(mfa *models.UserMFA) error {
+	return DB.Save(mfa).Error
+}
+
+// EnableUserMFA 启用二次验证，并用新的恢复码替换旧恢复码
+func EnableUserMFA(userID uint, step int64, codeHashes []string) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&models.UserMFA{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]interface{}{
+				"enabled":        true,
+				"last_used_step": step,
+				"confirmed_at":   now,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]models.MFARecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, models.MFARecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseMFAStep 记录已使用的时间步，时间步不大于上次记录时返回 false（验证码被重放）
+func UseMFAStep(userID uint, step int64) (bool, error) {
+	result := DB.Model(&models.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UseRecoveryCode 使用一次恢复码，恢复码不存在或已使用时返回 false
+func UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	result := DB.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UseMFAChallenge 记录挑战令牌已使用，并顺带清理已过期的记录；令牌已使用过时返回 false
+func UseMFAChallenge(userID uint, jti string, expiresAt time.Time) (bool, error) {
+	if err := DB.Where("expires_at < ?", time.Now()).Delete(&models.MFAChallengeUse{}).Error; err != nil {
+		return false, err
+	}
+
+	result := DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.MFAChallengeUse{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountRecoveryCodes 统计用户剩余可用的恢复码数量
+func CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+
+	result := DB.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// DeleteUserMFA 删除用户二次验证配置及恢复码
+func DeleteUserMFA(userID uint) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserMFA{}).Error
+	})
+}
+
+// ChangeUserType 修改用户类型，并删除用户的二次验证配置及恢复码，二次验证需按新类型的规则重新绑定
+func ChangeUserType(userID uint, userType models.UserType) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("user_type", userType).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserMFA{}).Error
+	})
+}
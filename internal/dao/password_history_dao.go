@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"go-web/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ListPasswordHistory 获取用户最近 limit 个历史密码哈希（按替换时间倒序）
+func ListPasswordHistory(userID uint, limit int) ([]string, error) {
+	var hashes []string
+
+	result := DB.Model(&models.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return hashes, nil
+}
+
+// UpdateUserPassword 更新用户密码，并将旧密码写入历史记录（只保留最近 keep 条，keep 为0时不记录）
+func UpdateUserPassword(userID uint, passwordHash string, keep int) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		return replacePassword(tx, userID, passwordHash, keep, nil)
+	})
+}
+
+// UpgradePasswordHash 以新的加密参数重新保存同一密码（不记录历史）
+// 仅当密码未被并发修改时才更新
+func UpgradePasswordHash(userID uint, oldHash, newHash string) error {
+	return DB.Model(&models.User{}).
+		Where("id = ? AND password_hash = ?", userID, oldHash).
+		Update("password_hash", newHash).Error
+}
+
+// replacePassword 在事务中替换用户密码，extra 为需要一并更新的其他字段
+func replacePassword(tx *gorm.DB, userID uint, passwordHash string, keep int, extra map[string]interface{}) error {
+	if keep > 0 {
+		var user models.User
+		if err := tx.Select("id", "password_hash").First(&user, userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.PasswordHistory{
+			UserID:       userID,
+			PasswordHash: user.PasswordHash,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	updates := map[string]interface{}{"password_hash": passwordHash}
+	for column, value := range extra {
+		updates[column] = value
+	}
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	// 清理超出保留数量的历史记录（keep 为0时清空）
+	var stale []uint
+	if err := tx.Model(&models.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Offset(keep).
+		Pluck("id", &stale).Error; err != nil {
+		return err
+	}
+	if len(stale) > 0 {
+		return tx.Delete(&models.PasswordHistory{}, stale).Error
+	}
+	return nil
+}
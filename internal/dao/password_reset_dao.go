@@ -0,0 +1,58 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"go-web/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrResetTokenUsed 重置令牌已被使用
+var ErrResetTokenUsed = errors.New("重置令牌已被使用")
+
+// CreatePasswordResetToken 保存密码重置令牌
+func CreatePasswordResetToken(resetToken *models.PasswordResetToken) error {
+	return DB.Create(resetToken).Error
+}
+
+// GetPasswordResetToken 根据令牌哈希获取密码重置令牌
+func GetPasswordResetToken(tokenHash string) (*models.PasswordResetToken, error) {
+	var resetToken models.PasswordResetToken
+
+	result := DB.Where("token_hash = ?", tokenHash).First(&resetToken)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &resetToken, nil
+}
+
+// ResetPassword 使用重置令牌更新用户密码，旧密码写入历史记录（只保留最近 keep 条）
+// 令牌被并发使用时返回 ErrResetTokenUsed；成功后该用户其他未使用的重置令牌一并作废
+func ResetPassword(resetToken *models.PasswordResetToken, passwordHash string, keep int) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", resetToken.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrResetTokenUsed
+		}
+
+		if err := replacePassword(tx, resetToken.UserID, passwordHash, keep, map[string]interface{}{
+			"password_reset_required": false,
+		}); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", resetToken.UserID).
+			Update("used_at", now).Error
+	})
+}
@@ -0,0 +1,21 @@
+package dao
+
+import (
+	"go-web/internal/models"
+)
+
+// GetRolePermissions 获取角色拥有的全部权限名称
+func GetRolePermissions(roleName string) ([]string, error) {
+	var permissions []string
+
+	result := DB.Model(&models.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name = ?", roleName).
+		Pluck("permissions.name", &permissions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return permissions, nil
+}
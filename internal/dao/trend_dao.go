@@ -0,0 +1,136 @@
+package dao
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PeriodLayout 月度数据表 period 列的格式
+const PeriodLayout = "2006-01"
+
+// TrendFilter 趋势查询条件
+type TrendFilter struct {
+	From     string   // 起始年月（含），为空时取截止年月前 11 个月
+	To       string   // 截止年月（含），为空时取数据中最新的年月
+	Names    []string // 指定名称，为空时取区间合计最高的 Top 个
+	Contains string   // 名称模糊匹配
+	Top      int
+}
+
+// TrendPoint 某个名称在某月的数值
+type TrendPoint struct {
+	Period string
+	Name   string
+	Value  float64
+}
+
+// TrendResult 趋势查询结果，Names 按区间合计降序排列
+type TrendResult struct {
+	From   string
+	To     string
+	Names  []AnalyticsValue // 名称及区间合计
+	Points []TrendPoint
+}
+
+// listTrend 查询月度数据表在时间区间内的趋势数据
+// 表中没有数据时返回的 From/To 为空
+func listTrend[T any](filter TrendFilter, nameColumn, valueColumn string) (*TrendResult, error) {
+	result := &TrendResult{From: filter.From, To: filter.To}
+
+	if result.To == "" {
+		latest, err := latestPeriod[T]()
+		if err != nil {
+			return nil, err
+		}
+		if latest == "" {
+			return &TrendResult{}, nil
+		}
+		result.To = latest
+	}
+	if result.From == "" {
+		to, err := time.Parse(PeriodLayout, result.To)
+		if err != nil {
+			return nil, err
+		}
+		result.From = to.AddDate(0, -11, 0).Format(PeriodLayout)
+	}
+
+	scoped := func() *gorm.DB {
+		db := DB.Model(new(T)).Where("period BETWEEN ? AND ?", result.From, result.To)
+		if filter.Contains != "" {
+			db = db.Where(nameColumn+" LIKE ?", "%"+escapeLike(filter.Contains)+"%")
+		}
+		if len(filter.Names) > 0 {
+			db = db.Where(nameColumn+" IN ?", filter.Names)
+		}
+		return db
+	}
+
+	// 按区间合计排序确定序列，未指定名称时只取前 Top 个
+	query := scoped().
+		Select(nameColumn + " AS name, SUM(" + valueColumn + ") AS value").
+		Group(nameColumn).
+		Order("value DESC, name ASC")
+	if len(filter.Names) == 0 && filter.Top > 0 {
+		query = query.Limit(filter.Top)
+	}
+	if err := query.Scan(&result.Names).Error; err != nil {
+		return nil, err
+	}
+	if len(result.Names) == 0 {
+		return result, nil
+	}
+
+	names := make([]string, len(result.Names))
+	for i, n := range result.Names {
+		names[i] = n.Name
+	}
+	if err := scoped().
+		Where(nameColumn+" IN ?", names).
+		Select("period, " + nameColumn + " AS name, SUM(" + valueColumn + ") AS value").
+		Group("period, " + nameColumn).
+		Order("period ASC").
+		Scan(&result.Points).Error; err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// latestPeriod 月度数据表中最新的年月，表中没有数据时返回空字符串
+func latestPeriod[T any]() (string, error) {
+	var latest *string
+	if err := DB.Model(new(T)).Select("MAX(period)").Scan(&latest).Error; err != nil {
+		return "", err
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return *latest, nil
+}
+
+// listPeriodValues 获取月度数据表某个年月的全部名称及数值，period 为空时取最新年月
+// 返回实际查询的年月，表中没有数据时为空
+func listPeriodValues[T any](period, nameColumn, valueColumn string) (string, []AnalyticsValue, error) {
+	if period == "" {
+		latest, err := latestPeriod[T]()
+		if err != nil || latest == "" {
+			return "", nil, err
+		}
+		period = latest
+	}
+
+	var values []AnalyticsValue
+	result := DB.Model(new(T)).
+		Where("period = ?", period).
+		Select(nameColumn + " AS name, SUM(" + valueColumn + ") AS value").
+		Group(nameColumn).
+		Order("value DESC, name ASC").
+		Scan(&values)
+	if result.Error != nil {
+		return "", nil, result.Error
+	}
+
+	return period, values, nil
+}
@@ -0,0 +1,157 @@
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"go-web/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GetUserByID 根据ID获取用户
+func GetUserByID(id uint) (*models.User, error) {
+	var user models.User
+
+	result := DB.First(&user, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+// UserListFilter 用户列表过滤条件
+type UserListFilter struct {
+	UserType    string
+	Status      string
+	CreatedFrom *time.Time // 含
+	CreatedTo   *time.Time // 不含
+	Search      string     // 用户名/邮箱模糊匹配
+}
+
+// apply 将过滤条件应用到查询
+func (f UserListFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.UserType != "" {
+		db = db.Where("user_type = ?", f.UserType)
+	}
+	if f.Status != "" {
+		db = db.Where("status = ?", f.Status)
+	}
+	if f.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		db = db.Where("created_at < ?", *f.CreatedTo)
+	}
+	if f.Search != "" {
+		pattern := "%" + escapeLike(f.Search) + "%"
+		db = db.Where("(username LIKE ? OR email LIKE ?)", pattern, pattern)
+	}
+	return db
+}
+
+// ListUsers 按偏移量分页获取用户列表，同时返回满足条件的总数
+func ListUsers(filter UserListFilter, orderBy string, offset, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := filter.apply(DB.Model(&models.User{}))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.Order(orderBy).Offset(offset).Limit(limit).Find(&users)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return users, total, nil
+}
+
+// ListUsersByCursor 按主键游标分页获取用户列表（keyset 分页，适用于大表）
+// afterID 为 0 表示第一页；desc 为 true 时按 ID 降序
+func ListUsersByCursor(filter UserListFilter, afterID uint, desc bool, limit int) ([]models.User, error) {
+	var users []models.User
+
+	query := filter.apply(DB.Model(&models.User{}))
+	order := "id ASC"
+	if desc {
+		order = "id DESC"
+		if afterID > 0 {
+			query = query.Where("id < ?", afterID)
+		}
+	} else if afterID > 0 {
+		query = query.Where("id > ?", afterID)
+	}
+
+	result := query.Order(order).Limit(limit).Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return users, nil
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// VerifyUserEmail 完成邮箱验证：等待验证的新注册账户同时被激活，修改过邮箱的账户只记录验证时间
+// 账户邮箱已变更或已验证时返回 false
+func VerifyUserEmail(userID uint, email string) (bool, error) {
+	now := time.Now()
+	result := DB.Model(&models.User{}).
+		Where("id = ? AND email = ? AND email_verification_pending = ?", userID, email, true).
+		Updates(map[string]interface{}{
+			"status":                     models.UserStatusActive,
+			"email_verification_pending": false,
+			"email_verified_at":          now,
+		})
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.RowsAffected > 0, result.Error
+	}
+
+	result = DB.Model(&models.User{}).
+		Where("id = ? AND email = ? AND email_verified_at IS NULL", userID, email).
+		Update("email_verified_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteUnverifiedUsers 彻底删除在 before 之前注册且仍未验证邮箱的账户，释放其用户名和邮箱
+func DeleteUnverifiedUsers(before time.Time) (int64, error) {
+	result := DB.Unscoped().
+		Where("email_verification_pending = ? AND status = ? AND created_at < ?", true, models.UserStatusInactive, before).
+		Delete(&models.User{})
+	return result.RowsAffected, result.Error
+}
+
+// GetUserByEmail 根据邮箱获取用户
+func GetUserByEmail(email string) (*models.User, error) {
+	var user models.User
+
+	result := DB.Where("email = ?", email).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+// UsernameTaken 判断用户名是否已被占用（含已删除的用户）
+func UsernameTaken(username string) (bool, error) {
+	var count int64
+	err := DB.Unscoped().Model(&models.User{}).Where("username = ?", username).Count(&count).Error
+	return count > 0, err
+}
+
+// EmailTaken 判断邮箱是否已被占用（含已删除的用户）
+func EmailTaken(email string) (bool, error) {
+	var count int64
+	err := DB.Unscoped().Model(&models.User{}).Where("email = ?", email).Count(&count).Error
+	return count > 0, err
+}
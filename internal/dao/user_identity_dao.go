@@ -0,0 +1,45 @@
+package dao
+
+import (
+	"time"
+
+	"go-web/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GetUserIdentity 根据提供方和 sub 获取外部身份绑定
+func GetUserIdentity(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+
+	result := DB.Where("provider = ? AND subject = ?", provider, subject).First(&identity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &identity, nil
+}
+
+// CreateUserIdentity 为已有用户绑定外部身份
+func CreateUserIdentity(identity *models.UserIdentity) error {
+	return DB.Create(identity).Error
+}
+
+// CreateUserWithIdentity 在同一事务中创建用户并绑定外部身份
+func CreateUserWithIdentity(user *models.User, identity *models.UserIdentity) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
+
+// TouchUserIdentity 更新外部身份的最近登录时间和邮箱
+func TouchUserIdentity(id uint, email string) error {
+	return DB.Model(&models.UserIdentity{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":         email,
+		"last_login_at": time.Now(),
+	}).Error
+}
@@ -0,0 +1,185 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"go-web/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSessionRotated 会话已被其他请求轮换或吊销
+var ErrSessionRotated = errors.New("会话已被轮换或吊销")
+
+// CreateSession 创建用户会话
+func CreateSession(session *models.UserSession) error {
+	return DB.Omit(clause.Associations).Create(session).Error
+}
+
+// GetSessionByRefreshToken 根据刷新令牌哈希获取会话
+func GetSessionByRefreshToken(refreshTokenHash string) (*models.UserSession, error) {
+	var session models.UserSession
+
+	result := DB.Where("refresh_token = ?", refreshTokenHash).First(&session)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &session, nil
+}
+
+// RotateSession 用新会话替换旧会话，旧会话标记为已轮换
+// 旧会话已被轮换或吊销时返回 ErrSessionRotated，且不会写入新会话
+func RotateSession(old *models.UserSession, next *models.UserSession) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(next).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.UserSession{}).
+			Where("id = ? AND revoked_at IS NULL", old.ID).
+			Updates(map[string]interface{}{
+				"revoked_at":     now,
+				"revoke_reason":  models.SessionRevokeRotated,
+				"replaced_by_id": next.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSessionRotated
+		}
+		return nil
+	})
+}
+
+// RevokeSessionFamily 吊销同一会话族下所有未吊销的会话
+func RevokeSessionFamily(familyID string, reason string) error {
+	return DB.Model(&models.UserSession{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]interface{}{
+			"revoked_at":    time.Now(),
+			"revoke_reason": reason,
+		}).Error
+}
+
+// RevokedSessionFamily 已吊销的会话族
+type RevokedSessionFamily struct {
+	FamilyID  string
+	RevokedAt time.Time
+}
+
+// ListRevokedSessionFamilies 获取指定时间之后被吊销的会话族（不含正常轮换）
+func ListRevokedSessionFamilies(since time.Time) ([]RevokedSessionFamily, error) {
+	var families []RevokedSessionFamily
+
+	result := DB.Model(&models.UserSession{}).
+		Select("family_id, MAX(revoked_at) AS revoked_at").
+		Where("revoked_at >= ? AND revoke_reason <> ?", since, models.SessionRevokeRotated).
+		Group("family_id").
+		Scan(&families)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return families, nil
+}
+
+// RevokeUserSessions 吊销用户所有未吊销的会话族，exceptFamilyID 非空时保留该会话族
+// 返回被吊销的会话族ID列表
+func RevokeUserSessions(userID uint, reason string, exceptFamilyID string) ([]string, error) {
+	var familyIDs []string
+
+	query := DB.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptFamilyID != "" {
+		query = query.Where("family_id <> ?", exceptFamilyID)
+	}
+	if err := query.Distinct().Pluck("family_id", &familyIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(familyIDs) == 0 {
+		return familyIDs, nil
+	}
+
+	result := DB.Model(&models.UserSession{}).
+		Where("family_id IN ? AND revoked_at IS NULL", familyIDs).
+		Updates(map[string]interface{}{
+			"revoked_at":    time.Now(),
+			"revoke_reason": reason,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return familyIDs, nil
+}
+
+// ActiveSession 一个仍有效的会话族：当前有效的一行及该次登录的开始时间
+type ActiveSession struct {
+	models.UserSession
+	StartedAt time.Time
+}
+
+// ListActiveSessions 获取用户仍有效（未吊销且刷新令牌未过期）的会话族，按最近活跃时间倒序
+func ListActiveSessions(userID uint) ([]ActiveSession, error) {
+	var current []models.UserSession
+	if err := DB.Where("user_id = ? AND revoked_at IS NULL AND refresh_expires_at > ?", userID, time.Now()).
+		Find(&current).Error; err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return []ActiveSession{}, nil
+	}
+
+	familyIDs := make([]string, 0, len(current))
+	for _, session := range current {
+		familyIDs = append(familyIDs, session.FamilyID)
+	}
+
+	// 会话族中最早一行的创建时间即为登录时间
+	var starts []struct {
+		FamilyID  string
+		StartedAt time.Time
+	}
+	if err := DB.Model(&models.UserSession{}).
+		Select("family_id, MIN(created_at) AS started_at").
+		Where("family_id IN ?", familyIDs).
+		Group("family_id").
+		Scan(&starts).Error; err != nil {
+		return nil, err
+	}
+	startedAt := make(map[string]time.Time, len(starts))
+	for _, s := range starts {
+		startedAt[s.FamilyID] = s.StartedAt
+	}
+
+	sessions := make([]ActiveSession, 0, len(current))
+	for _, session := range current {
+		started, ok := startedAt[session.FamilyID]
+		if !ok {
+			started = session.CreatedAt
+		}
+		sessions = append(sessions, ActiveSession{UserSession: session, StartedAt: started})
+	}
+	return sessions, nil
+}
+
+// UserOwnsSessionFamily 判断会话族是否属于指定用户且仍未吊销
+func UserOwnsSessionFamily(userID uint, familyID string) (bool, error) {
+	var count int64
+	err := DB.Model(&models.UserSession{}).
+		Where("user_id = ? AND family_id = ? AND revoked_at IS NULL", userID, familyID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// TouchSession 记录会话族当前有效行的最近活跃时间
+func TouchSession(familyID string, seenAt time.Time) error {
+	return DB.Model(&models.UserSession{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("last_seen_at", seenAt).Error
+}
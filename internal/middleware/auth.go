@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"go-web/internal/auth"
+	"go-web/internal/dao"
+	"go-web/internal/models"
+	"go-web/pkg/logger"
+	"go-web/pkg/token"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// 上下文中保存认证信息的键
+const (
+	ContextUserKey   = "currentUser"
+	ContextClaimsKey = "tokenClaims"
+	ContextAPIKeyKey = "apiKey"
+)
+
+// 认证失败原因（写入 ErrorResponse.Error，供前端识别）
+const (
+	ReasonTokenMissing  = "token_missing"
+	ReasonTokenInvalid  = "token_invalid"
+	ReasonTokenExpired  = "token_expired"
+	ReasonTokenRevoked  = "token_revoked"
+	ReasonUserNotFound  = "user_not_found"
+	ReasonUserInactive  = "user_inactive"
+	ReasonInternalError = "internal_error"
+
+	ReasonAPIKeyInvalid    = "api_key_invalid"
+	ReasonAPIKeyExpired    = "api_key_expired"
+	ReasonAPIKeyRevoked    = "api_key_revoked"
+	ReasonAPIKeyNotAllowed = "api_key_not_allowed"
+)
+
+// APIKeyHeader 个人 API 密钥请求头
+const APIKeyHeader = "X-API-Key"
+
+// AuthRequired 校验 X-API-Key 或 Authorization: Bearer <token>，并将当前用户写入上下文
+// 同时提供两者时以 API 密钥为准
+func AuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var userID uint
+		if apiKey := strings.TrimSpace(c.GetHeader(APIKeyHeader)); apiKey != "" {
+			key, ok := authenticateAPIKey(c, apiKey)
+			if !ok {
+				return
+			}
+			c.Set(ContextAPIKeyKey, key)
+			userID = key.UserID
+		} else {
+			claims, ok := authenticateBearer(c)
+			if !ok {
+				return
+			}
+			c.Set(ContextClaimsKey, claims)
+			userID = claims.UserID
+			auth.TouchSession(claims.SessionID)
+		}
+
+		user, err := dao.GetUserByID(userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				abortWithError(c, http.StatusUnauthorized, "用户不存在", ReasonUserNotFound)
+				return
+			}
+			logger.Errorw("查询认证用户失败", "error", err, "user_id", userID)
+			abortWithError(c, http.StatusInternalServerError, "认证失败", ReasonInternalError)
+			return
+		}
+
+		if user.Status != models.UserStatusActive {
+			logger.Warnw("未激活用户访问受保护接口", "user_id", user.ID, "status", user.Status)
+			abortWithError(c, http.StatusForbidden, "账户未激活", ReasonUserInactive)
+			return
+		}
+
+		c.Set(ContextUserKey, user)
+		c.Next()
+	}
+}
+
+// SessionRequired 要求请求使用登录会话的访问令牌，拒绝 API 密钥，需放在 AuthRequired 之后
+// 用于账户管理类接口，避免泄露的 API 密钥被用来修改密码、创建新密钥等
+func SessionRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := CurrentClaims(c); !ok {
+			abortWithError(c, http.StatusForbidden, "该接口不支持 API 密钥访问", ReasonAPIKeyNotAllowed)
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticateBearer 校验 Bearer 访问令牌及其会话，失败时终止请求
+func authenticateBearer(c *gin.Context) (*token.Claims, bool) {
+	tokenString, ok := bearerToken(c)
+	if !ok {
+		abortWithError(c, http.StatusUnauthorized, "未提供访问令牌", ReasonTokenMissing)
+		return nil, false
+	}
+
+	claims, err := token.ParseTokenOfType(tokenString, token.TypeAccess)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			abortWithError(c, http.StatusUnauthorized, "访问令牌已过期", ReasonTokenExpired)
+			return nil, false
+		}
+		logger.Warnw("访问令牌校验失败", "error", err, "ip", c.ClientIP())
+		abortWithError(c, http.StatusUnauthorized, "访问令牌无效", ReasonTokenInvalid)
+		return nil, false
+	}
+
+	// 检查令牌所属会话是否已被吊销（登出、在所有设备登出等）
+	if claims.SessionID == "" {
+		abortWithError(c, http.StatusUnauthorized, "访问令牌无效", ReasonTokenInvalid)
+		return nil, false
+	}
+	if auth.Revocations.IsRevoked(claims.SessionID) {
+		abortWithError(c, http.StatusUnauthorized, "访问令牌已失效", ReasonTokenRevoked)
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// authenticateAPIKey 校验 API 密钥，失败时终止请求
+func authenticateAPIKey(c *gin.Context, plain string) (*models.APIKey, bool) {
+	key, err := auth.AuthenticateAPIKey(plain, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrAPIKeyExpired):
+			abortWithError(c, http.StatusUnauthorized, "API 密钥已过期", ReasonAPIKeyExpired)
+		case errors.Is(err, auth.ErrAPIKeyRevoked):
+			abortWithError(c, http.StatusUnauthorized, "API 密钥已吊销", ReasonAPIKeyRevoked)
+		case errors.Is(err, auth.ErrAPIKeyInvalid):
+			logger.Warnw("API 密钥校验失败", "ip", c.ClientIP())
+			abortWithError(c, http.StatusUnauthorized, "API 密钥无效", ReasonAPIKeyInvalid)
+		default:
+			logger.Errorw("查询 API 密钥失败", "error", err)
+			abortWithError(c, http.StatusInternalServerError, "认证失败", ReasonInternalError)
+		}
+		return nil, false
+	}
+	return key, true
+}
+
+// CurrentUser 获取认证中间件写入的当前用户
+func CurrentUser(c *gin.Context) (*models.User, bool) {
+	value, exists := c.Get(ContextUserKey)
+	if !exists {
+		return nil, false
+	}
+	user, ok := value.(*models.User)
+	return user, ok
+}
+
+// CurrentClaims 获取当前请求的令牌声明
+func CurrentClaims(c *gin.Context) (*token.Claims, bool) {
+	value, exists := c.Get(ContextClaimsKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*token.Claims)
+	return claims, ok
+}
+
+// CurrentAPIKey 获取当前请求使用的 API 密钥，使用访问令牌认证时返回 false
+func CurrentAPIKey(c *gin.Context) (*models.APIKey, bool) {
+	value, exists := c.Get(ContextAPIKeyKey)
+	if !exists {
+		return nil, false
+	}
+	key, ok := value.(*models.APIKey)
+	return key, ok
+}
+
+// bearerToken 从 Authorization 头中提取 Bearer 令牌
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	scheme, tokenString, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	tokenString = strings.TrimSpace(tokenString)
+	return tokenString, tokenString != ""
+}
+
+// abortWithError 终止请求并返回统一的错误响应
+func abortWithError(c *gin.Context, status int, message, reason string) {
+	c.AbortWithStatusJSON(status, models.ErrorResponse{
+		Code:    status,
+		Message: message,
+		Error:   reason,
+	})
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go-web/internal/auth"
+	"go-web/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReasonPermissionDenied 权限不足
+const ReasonPermissionDenied = "permission_denied"
+
+// RequirePermission 要求当前用户的角色拥有全部指定权限，需放在 AuthRequired 之后
+// 使用 API 密钥访问时，密钥的权限范围也必须包含这些权限
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := CurrentUser(c)
+		if !ok {
+			abortWithError(c, http.StatusUnauthorized, "未提供访问令牌", ReasonTokenMissing)
+			return
+		}
+
+		allowed, err := auth.HasPermissions(string(user.UserType), permissions...)
+		if err != nil {
+			logger.Errorw("查询角色权限失败", "error", err, "role", user.UserType)
+			abortWithError(c, http.StatusInternalServerError, "权限校验失败", ReasonInternalError)
+			return
+		}
+		if !allowed {
+			logger.Warnw("用户权限不足", "user_id", user.ID, "role", user.UserType, "required", permissions)
+			abortWithError(c, http.StatusForbidden, "权限不足", ReasonPermissionDenied)
+			return
+		}
+
+		if key, ok := CurrentAPIKey(c); ok {
+			for _, permission := range permissions {
+				if !key.HasScope(permission) {
+					logger.Warnw("API 密钥权限范围不足", "user_id", user.ID, "key_id", key.ID, "required", permissions)
+					abortWithError(c, http.StatusForbidden, "API 密钥权限不足", ReasonPermissionDenied)
+					return
+				}
+			}
+		}
+
+		c.Next()
+	}
+}
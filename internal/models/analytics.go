@@ -0,0 +1,42 @@
+package models
+
+import (
+	"go-web/pkg/stats"
+)
+
+// AnalyticsMetrics 分布数据项的占比和排名，仅在请求 ?with= 时返回
+type AnalyticsMetrics struct {
+	Share           *float64 `json:"share,omitempty"`            // 占总量的百分比
+	CumulativeShare *float64 `json:"cumulative_share,omitempty"` // 按数值降序累计的百分比（帕累托图）
+	Rank            *int     `json:"rank,omitempty"`             // 按数值降序的排名，数值相同排名相同
+	Others          bool     `json:"others,omitempty"`           // 是否为合并其余数据项的“其他”行
+	GroupedCount    int      `json:"grouped_count,omitempty"`    // “其他”行合并的数据项数量
+}
+
+// AnalyticsItem 分布数据项，各分析数据模型通过嵌入 AnalyticsMetrics 并实现名称和数值访问满足该接口
+type AnalyticsItem interface {
+	ItemName() string
+	ItemValue() float64
+	SetItem(name string, value float64)
+	SetMetrics(metrics AnalyticsMetrics)
+}
+
+// “其他”行的名称
+const (
+	OthersLabel   = "其他"
+	OthersLabelEn = "Others"
+)
+
+// SetMetrics 设置占比和排名
+func (m *AnalyticsMetrics) SetMetrics(metrics AnalyticsMetrics) {
+	*m = metrics
+}
+
+// AnalyticsResponse 分析数据分页响应，Summary 仅在请求 ?with= 时返回
+type AnalyticsResponse struct {
+	Code       int                `json:"code"`
+	Message    string             `json:"message"`
+	Data       interface{}        `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+	Summary    *stats.Summary     `json:"summary,omitempty"`
+}
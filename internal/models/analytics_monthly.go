@@ -0,0 +1,103 @@
+package models
+
+// 按月汇总的分析数据，Period 为 YYYY-MM 格式的年月
+
+// BrandSalesMonthly 品牌月度销售数据
+type BrandSalesMonthly struct {
+	Period     string  `json:"period" gorm:"column:period;type:char(7)"`              // 年月
+	BrandName  string  `json:"brand_name" gorm:"column:brand_name;type:varchar(255)"` // 品牌名称
+	TotalSales float64 `json:"total_sales" gorm:"column:total_sales;type:decimal"`    // 总销售额
+}
+
+// TableName 指定表名
+func (BrandSalesMonthly) TableName() string {
+	return "brand_sales_monthly"
+}
+
+// CitySalesMonthly 城市月度销售数据
+type CitySalesMonthly struct {
+	Period string  `json:"period" gorm:"column:period;type:char(7)"`  // 年月
+	City   string  `json:"city" gorm:"column:city;type:varchar(255)"` // 城市名称
+	Sales  float64 `json:"sales" gorm:"column:sales;type:decimal"`    // 销售数量
+}
+
+// TableName 指定表名
+func (CitySalesMonthly) TableName() string {
+	return "city_sales_monthly"
+}
+
+// CarLevelMonthly 汽车级别月度分布数据
+type CarLevelMonthly struct {
+	Period   string `json:"period" gorm:"column:period;type:char(7)"`            // 年月
+	CarLevel string `json:"car_level" gorm:"column:car_level;type:varchar(255)"` // 汽车级别
+	CarCount int64  `json:"car_count" gorm:"column:car_count;type:bigint"`       // 汽车数量
+}
+
+// TableName 指定表名
+func (CarLevelMonthly) TableName() string {
+	return "ads_car_level_monthly"
+}
+
+// EnergyTypeMonthly 能源类型月度分布数据
+type EnergyTypeMonthly struct {
+	Period     string `json:"period" gorm:"column:period;type:char(7)"`                // 年月
+	EnergyName string `json:"energy_name" gorm:"column:energy_type;type:varchar(255)"` // 能源类型名称
+	Count      int64  `json:"count" gorm:"column:car_count;type:bigint"`               // 数量
+}
+
+// TableName 指定表名
+func (EnergyTypeMonthly) TableName() string {
+	return "ads_car_energy_monthly"
+}
+
+// TrendSeries 趋势图中的一条数据序列，Data 与 TrendData.Periods 一一对应
+type TrendSeries struct {
+	Name  string    `json:"name"`
+	Data  []float64 `json:"data"`
+	Total float64   `json:"total"` // 区间合计
+}
+
+// TrendData 趋势数据，缺失月份的值为0
+type TrendData struct {
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Periods []string      `json:"periods"`
+	Series  []TrendSeries `json:"series"`
+	Totals  []float64     `json:"totals"` // 每月所有序列的合计
+}
+
+// 对比状态
+const (
+	ComparisonStatusNew        = "new"        // 本期新出现
+	ComparisonStatusDropped    = "dropped"    // 本期消失
+	ComparisonStatusContinuing = "continuing" // 两期都存在
+	ComparisonStatusUnknown    = "unknown"    // 对比年月没有任何数据，无法判断
+)
+
+// ComparisonItem 某个名称在两个年月间的对比
+type ComparisonItem struct {
+	Name         string   `json:"name"`
+	Current      float64  `json:"current"`
+	Previous     float64  `json:"previous"`
+	Delta        float64  `json:"delta"`
+	Growth       *float64 `json:"growth"`        // 增长百分比，上期为0时为 null
+	CurrentRank  *int     `json:"current_rank"`  // 本期排名，本期不存在时为 null
+	PreviousRank *int     `json:"previous_rank"` // 上期排名，上期不存在时为 null
+	RankChange   *int     `json:"rank_change"`   // 排名变化，正数表示上升
+	Status       string   `json:"status"`
+}
+
+// ComparisonData 两个年月的对比结果
+type ComparisonData struct {
+	Mode              string           `json:"mode"`
+	Period            string           `json:"period"`
+	ComparePeriod     string           `json:"compare_period"`
+	PreviousAvailable bool             `json:"previous_available"` // 对比年月是否有数据，无数据时增长率均为 null、状态为 unknown
+	Current           float64          `json:"current"`            // 本期合计
+	Previous          float64          `json:"previous"`           // 上期合计
+	Delta             float64          `json:"delta"`
+	Growth            *float64         `json:"growth"`
+	Items             []ComparisonItem `json:"items"`
+	NewEntrants       []string         `json:"new_entrants"`
+	Dropped           []string         `json:"dropped"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKey 个人 API 密钥（只保存哈希），供脚本和 BI 工具通过 X-API-Key 访问分析接口
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"size:100;not null"`
+	Prefix     string     `json:"prefix" gorm:"size:16;not null"`        // 密钥前缀，用于在列表中辨认密钥
+	KeyHash    string     `json:"-" gorm:"size:64;uniqueIndex;not null"` // 密钥 SHA-256 哈希
+	Scopes     string     `json:"-" gorm:"size:255;not null"`            // 以逗号分隔的权限名
+	ScopeList  []string   `json:"scopes" gorm:"-"`                       // 接口输出用
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`                  // 为空表示永不过期
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`                // 最近使用时间
+	LastUsedIP string     `json:"last_used_ip,omitempty" gorm:"size:45"` // 最近使用IP
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`                  // 吊销时间
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// AfterFind 查询后展开权限列表
+func (k *APIKey) AfterFind(_ *gorm.DB) error {
+	k.ScopeList = k.SplitScopes()
+	return nil
+}
+
+// SplitScopes 返回密钥的权限列表
+func (k *APIKey) SplitScopes() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// HasScope 判断密钥是否拥有指定权限
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.SplitScopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
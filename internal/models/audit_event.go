@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 审计操作
+const (
+	AuditActionRegister       = "auth.register"
+	AuditActionLogin          = "auth.login"
+	AuditActionLogout         = "auth.logout"
+	AuditActionLogoutAll      = "auth.logout_all"
+	AuditActionTokenReuse     = "auth.refresh_token_reuse"
+	AuditActionMFAVerify      = "auth.mfa_verify"
+	AuditActionOAuthLogin     = "auth.oauth_login"
+	AuditActionEmailVerify    = "auth.email_verify"
+	AuditActionPasswordForgot = "auth.password_forgot"
+	AuditActionPasswordReset  = "auth.password_reset"
+	AuditActionPasswordChange = "user.password_change"
+	AuditActionProfileUpdate  = "user.profile_update"
+	AuditActionMFAEnable      = "user.mfa_enable"
+	AuditActionMFADisable     = "user.mfa_disable"
+	AuditActionUserActivate   = "user.activate"
+	AuditActionUserDeactivate = "user.deactivate"
+	AuditActionUserTypeChange = "user.type_change"
+	AuditActionUserForceReset = "user.force_password_reset"
+	AuditActionUserDelete     = "user.delete"
+	AuditActionAPIKeyCreate   = "api_key.create"
+	AuditActionAPIKeyRevoke   = "api_key.revoke"
+	AuditActionSessionRevoke  = "session.revoke"
+)
+
+// 审计结果
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+)
+
+// AuditEvent 审计事件（只追加，不修改）
+type AuditEvent struct {
+	ID            uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	ActorID       *uint           `json:"actor_id,omitempty" gorm:"index"` // 操作者，匿名操作为空
+	ActorUsername string          `json:"actor_username,omitempty" gorm:"size:100"`
+	Action        string          `json:"action" gorm:"size:64;not null;index"`
+	TargetType    string          `json:"target_type,omitempty" gorm:"size:32"`
+	TargetID      string          `json:"target_id,omitempty" gorm:"size:64"`
+	IPAddress     string          `json:"ip_address,omitempty" gorm:"size:45"`
+	UserAgent     string          `json:"user_agent,omitempty" gorm:"type:text"`
+	Result        string          `json:"result" gorm:"size:16;not null"`
+	Details       json.RawMessage `json:"details,omitempty" gorm:"type:json"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
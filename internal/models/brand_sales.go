@@ -4,9 +4,27 @@ package models
 type BrandSales struct {
 	BrandName  string  `json:"brand_name" gorm:"column:brand_name;type:varchar(255)"` // 品牌名称
 	TotalSales float64 `json:"total_sales" gorm:"column:total_sales;type:decimal"`    // 总销售额
+
+	AnalyticsMetrics `gorm:"-"`
 }
 
 // TableName 指定表名
 func (BrandSales) TableName() string {
 	return "brand_sales_sum"
 }
+
+// ItemName 返回品牌名称
+func (b BrandSales) ItemName() string {
+	return b.BrandName
+}
+
+// ItemValue 返回总销售额
+func (b BrandSales) ItemValue() float64 {
+	return b.TotalSales
+}
+
+// SetItem 设置名称和数值，用于构造合成数据项（如“其他”）
+func (b *BrandSales) SetItem(name string, value float64) {
+	b.BrandName = name
+	b.TotalSales = value
+}
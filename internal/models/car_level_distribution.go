@@ -1,12 +1,34 @@
 package models
 
+import (
+	"math"
+)
+
 // CarLevelDistribution 汽车级别分布模型
 type CarLevelDistribution struct {
 	CarLevel string `json:"car_level" gorm:"column:car_level;type:text"`   // 汽车级别
 	CarCount int64  `json:"car_count" gorm:"column:car_count;type:bigint"` // 汽车数量
+
+	AnalyticsMetrics `gorm:"-"`
 }
 
 // TableName 指定表名
 func (CarLevelDistribution) TableName() string {
 	return "ads_car_level_distribution"
 }
+
+// ItemName 返回汽车级别
+func (c CarLevelDistribution) ItemName() string {
+	return c.CarLevel
+}
+
+// ItemValue 返回汽车数量
+func (c CarLevelDistribution) ItemValue() float64 {
+	return float64(c.CarCount)
+}
+
+// SetItem 设置名称和数值，用于构造合成数据项（如“其他”）
+func (c *CarLevelDistribution) SetItem(name string, value float64) {
+	c.CarLevel = name
+	c.CarCount = int64(math.Round(value))
+}
@@ -0,0 +1,37 @@
+package models
+
+// CarSalesDetail 汽车销量明细事实表，每行为某年月、某城市、某车型、某能源类型的销量
+type CarSalesDetail struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	Period     string `json:"period" gorm:"column:period;type:char(7)"`                // 年月
+	BrandName  string `json:"brand_name" gorm:"column:brand_name;type:varchar(255)"`   // 品牌名称
+	Series     string `json:"series" gorm:"column:series;type:varchar(255)"`           // 车系
+	Model      string `json:"model" gorm:"column:model;type:varchar(255)"`             // 车型
+	City       string `json:"city" gorm:"column:city;type:varchar(255)"`               // 城市名称
+	CarLevel   string `json:"car_level" gorm:"column:car_level;type:varchar(255)"`     // 汽车级别
+	EnergyType string `json:"energy_type" gorm:"column:energy_type;type:varchar(255)"` // 能源类型
+	Sales      int64  `json:"sales" gorm:"column:sales;type:bigint"`                   // 销量
+}
+
+// TableName 指定表名
+func (CarSalesDetail) TableName() string {
+	return "dwd_car_sales_detail"
+}
+
+// BreakdownData 交叉分析矩阵，Cells[i][j] 为第 i 行、第 j 列的数值
+// 行、列合计和总计包含未返回的行列，Others 开启时未返回的行列合并为“其他”
+type BreakdownData struct {
+	Rows      string      `json:"rows"`    // 行维度
+	Cols      string      `json:"cols"`    // 列维度
+	Measure   string      `json:"measure"` // 度量
+	From      string      `json:"from,omitempty"`
+	To        string      `json:"to,omitempty"`
+	RowKeys   []string    `json:"row_keys"`
+	ColKeys   []string    `json:"col_keys"`
+	Cells     [][]float64 `json:"cells"`
+	RowTotals []float64   `json:"row_totals"`
+	ColTotals []float64   `json:"col_totals"`
+	Total     float64     `json:"total"`
+	RowCount  int         `json:"row_count"` // 行维度取值总数（截取前）
+	ColCount  int         `json:"col_count"` // 列维度取值总数（截取前）
+}
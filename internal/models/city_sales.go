@@ -4,9 +4,27 @@ package models
 type CitySales struct {
 	City  string  `json:"city" gorm:"column:city;type:varchar(255)"` // 城市名称
 	Sales float64 `json:"sales" gorm:"column:sales;type:decimal"`    // 销售数量
+
+	AnalyticsMetrics `gorm:"-"`
 }
 
 // TableName 指定表名
 func (CitySales) TableName() string {
 	return "citys_sv"
 }
+
+// ItemName 返回城市名称
+func (c CitySales) ItemName() string {
+	return c.City
+}
+
+// ItemValue 返回销售数量
+func (c CitySales) ItemValue() float64 {
+	return c.Sales
+}
+
+// SetItem 设置名称和数值，用于构造合成数据项（如“其他”）
+func (c *CitySales) SetItem(name string, value float64) {
+	c.City = name
+	c.Sales = value
+}
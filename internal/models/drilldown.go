@@ -0,0 +1,44 @@
+package models
+
+// UnknownLabel 参考表中没有对应上级的数据项归入的名称
+const UnknownLabel = "未知"
+
+// CityRegion 城市与省份、大区的对应关系参考表
+type CityRegion struct {
+	City     string `json:"city" gorm:"column:city;type:varchar(255);primaryKey"` // 城市名称，需与明细表中的城市名称一致
+	Province string `json:"province" gorm:"column:province;type:varchar(255)"`    // 省份
+	Region   string `json:"region" gorm:"column:region;type:varchar(255)"`        // 大区
+}
+
+// TableName 指定表名
+func (CityRegion) TableName() string {
+	return "dim_city_region"
+}
+
+// Breadcrumb 下钻路径上的一个节点及其汇总值
+type Breadcrumb struct {
+	Level string  `json:"level"` // 层级名称，根节点为空
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// DrilldownNode 当前节点的一个子节点
+type DrilldownNode struct {
+	Name         string  `json:"name"`
+	Value        float64 `json:"value"`
+	Share        float64 `json:"share"`                   // 占当前节点合计的百分比
+	HasChildren  bool    `json:"has_children"`            // 是否可以继续下钻
+	Others       bool    `json:"others,omitempty"`        // 是否为合并的“其他”节点
+	GroupedCount int     `json:"grouped_count,omitempty"` // “其他”节点合并的子节点数量
+}
+
+// DrilldownData 下钻查询结果
+type DrilldownData struct {
+	Hierarchy   string          `json:"hierarchy"`
+	Levels      []string        `json:"levels"`      // 层级从上到下的名称
+	Level       string          `json:"level"`       // 子节点所在层级，已是最底层时为空
+	Breadcrumbs []Breadcrumb    `json:"breadcrumbs"` // 从根节点到当前节点的路径，每级附带汇总值
+	Total       float64         `json:"total"`       // 当前节点合计
+	Children    []DrilldownNode `json:"children"`
+	ChildCount  int             `json:"child_count"` // 子节点总数（截取前）
+}
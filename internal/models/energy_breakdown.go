@@ -0,0 +1,53 @@
+package models
+
+// CityEnergyDistribution 城市能源类型分布模型
+type CityEnergyDistribution struct {
+	City       string `json:"city" gorm:"column:city;type:varchar(255)"`               // 城市名称
+	EnergyName string `json:"energy_name" gorm:"column:energy_type;type:varchar(255)"` // 能源类型名称
+	Count      int64  `json:"count" gorm:"column:car_count;type:bigint"`               // 数量
+}
+
+// TableName 指定表名
+func (CityEnergyDistribution) TableName() string {
+	return "ads_city_energy_distribution"
+}
+
+// CarLevelEnergyDistribution 汽车级别能源类型分布模型
+type CarLevelEnergyDistribution struct {
+	CarLevel   string `json:"car_level" gorm:"column:car_level;type:varchar(255)"`     // 汽车级别
+	EnergyName string `json:"energy_name" gorm:"column:energy_type;type:varchar(255)"` // 能源类型名称
+	Count      int64  `json:"count" gorm:"column:car_count;type:bigint"`               // 数量
+}
+
+// TableName 指定表名
+func (CarLevelEnergyDistribution) TableName() string {
+	return "ads_car_level_energy_distribution"
+}
+
+// EnergyMixItem 能源类型构成
+type EnergyMixItem struct {
+	EnergyName string  `json:"energy_name"`
+	Count      float64 `json:"count"`
+	Share      float64 `json:"share"` // 占总量的百分比
+	NEV        bool    `json:"nev"`   // 是否为新能源
+}
+
+// PenetrationItem 某个维度取值下的新能源渗透率
+type PenetrationItem struct {
+	Name        string  `json:"name"`
+	Total       float64 `json:"total"`
+	NEV         float64 `json:"nev"`
+	ICE         float64 `json:"ice"`
+	Penetration float64 `json:"penetration"` // 新能源占比（百分比）
+}
+
+// EnergyAnalysis 能源结构分析结果
+type EnergyAnalysis struct {
+	Total       float64           `json:"total"`
+	NEV         float64           `json:"nev"`         // 新能源（纯电、插混、增程、燃料电池等）
+	ICE         float64           `json:"ice"`         // 传统燃油（含普通混动）
+	Penetration float64           `json:"penetration"` // 新能源渗透率（百分比）
+	Mix         []EnergyMixItem   `json:"mix"`
+	By          string            `json:"by,omitempty"`        // 分维度统计的维度：city 或 car_level
+	Breakdown   []PenetrationItem `json:"breakdown,omitempty"` // 分维度渗透率
+}
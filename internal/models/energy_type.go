@@ -1,12 +1,34 @@
 package models
 
+import (
+	"math"
+)
+
 // EnergyType 能源类型分布模型
 type EnergyType struct {
 	EnergyName string `json:"energy_name" gorm:"column:energy_type;type:text"` // 能源类型名称字段
 	Count      int64  `json:"count" gorm:"column:car_count;type:bigint"`       // 数量字段
+
+	AnalyticsMetrics `gorm:"-"`
 }
 
 // TableName 指定表名
 func (EnergyType) TableName() string {
 	return "ads_car_energy_distribution"
 }
+
+// ItemName 返回能源类型名称
+func (e EnergyType) ItemName() string {
+	return e.EnergyName
+}
+
+// ItemValue 返回车辆数量
+func (e EnergyType) ItemValue() float64 {
+	return float64(e.Count)
+}
+
+// SetItem 设置名称和数值，用于构造合成数据项（如“其他”）
+func (e *EnergyType) SetItem(name string, value float64) {
+	e.EnergyName = name
+	e.Count = int64(math.Round(value))
+}
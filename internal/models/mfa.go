@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// UserMFA 用户 TOTP 二次验证配置
+type UserMFA struct {
+	UserID       uint       `json:"user_id" gorm:"primaryKey"`
+	Secret       string     `json:"-" gorm:"size:64;not null"`             // Base32 编码的 TOTP 密钥
+	Enabled      bool       `json:"enabled" gorm:"not null;default:false"` // 确认绑定后才启用
+	LastUsedStep int64      `json:"-" gorm:"not null;default:0"`           // 最近一次使用的时间步，防止验证码重放
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// MFARecoveryCode 一次性恢复码（只保存哈希）
+type MFARecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (MFARecoveryCode) TableName() string {
+	return "user_mfa_recovery_codes"
+}
+
+// MFAChallengeUse 已使用的二次验证挑战令牌，挑战令牌只能成功兑换一次
+type MFAChallengeUse struct {
+	JTI       string    `json:"jti" gorm:"primaryKey;size:32"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"` // 令牌过期后记录可清理
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (MFAChallengeUse) TableName() string {
+	return "user_mfa_challenge_uses"
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// PasswordHistory 用户历史密码（bcrypt 哈希），用于禁止重复使用最近的密码
+type PasswordHistory struct {
+	ID           uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       uint      `json:"user_id" gorm:"not null;index"`
+	PasswordHash string    `json:"-" gorm:"size:255;not null"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
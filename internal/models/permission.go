@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// 权限名称，格式为 资源:操作
+const (
+	PermUsersRead      = "users:read"      // 查看用户
+	PermUsersWrite     = "users:write"     // 管理用户
+	PermDatasetsImport = "datasets:import" // 导入数据集
+	PermAnalyticsRead  = "analytics:read"  // 查看分析数据
+	PermAuditRead      = "audit:read"      // 查看审计日志
+)
+
+// Role 角色模型，角色名与 UserType 一一对应
+type Role struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string    `json:"name" gorm:"size:50;uniqueIndex;not null"`
+	Description string    `json:"description" gorm:"size:255"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "roles"
+}
+
+// Permission 权限模型
+type Permission struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string    `json:"name" gorm:"size:100;uniqueIndex;not null"`
+	Description string    `json:"description" gorm:"size:255"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// RolePermission 角色与权限的关联
+type RolePermission struct {
+	RoleID       uint `json:"role_id" gorm:"primaryKey"`
+	PermissionID uint `json:"permission_id" gorm:"primaryKey"`
+}
+
+// TableName 指定表名
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
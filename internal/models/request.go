@@ -1,17 +1,28 @@
 package models
 
+// 密码字段格式，由请求中的 password_format 指定，登录、注册、重置和修改密码含义相同：
+// sha256（未指定时的默认值，兼容旧版客户端）表示密码字段为明文密码的 SHA-256 十六进制哈希；
+// plain 表示密码字段为明文，由服务端计算 SHA-256，设置密码时才能校验长度和字符类别。
+// 设置密码时是否接受 sha256 由 security.password_policy.allow_client_hash 控制
+const (
+	PasswordFormatSHA256 = "sha256"
+	PasswordFormatPlain  = "plain"
+)
+
 // RegisterRequest 注册请求
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=20"`
-	Email    string `json:"email" binding:"required,email"`
-	PasswordHash string `json:"password" binding:"required"`                    // SHA-256 哈希值
+	Username       string `json:"username" binding:"required,min=3,max=20"`
+	Email          string `json:"email" binding:"required,email"`
+	Password       string `json:"password" binding:"required"`                            // 格式由 password_format 指定
+	PasswordFormat string `json:"password_format" binding:"omitempty,oneof=sha256 plain"` // 默认 sha256
 	// UserType string `json:"user_type" binding:"omitempty,oneof=system app"` // 可选，默认为'app'
 }
 
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"` // 用户名或邮箱
-	PasswordHash string `json:"password" binding:"required"` // SHA-256 哈希值
+	Username       string `json:"username" binding:"required"`                            // 用户名或邮箱
+	Password       string `json:"password" binding:"required"`                            // 格式由 password_format 指定
+	PasswordFormat string `json:"password_format" binding:"omitempty,oneof=sha256 plain"` // 默认 sha256
 }
 
 // RefreshTokenRequest 刷新令牌请求
@@ -24,10 +35,27 @@ type ForgotPasswordRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
 
+// VerifyEmailRequest 邮箱验证请求
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerificationRequest 重新发送验证邮件请求
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// OAuthCallbackRequest 第三方登录回调请求（前端从回跳地址中取出 code 和 state 后提交）
+type OAuthCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
 // ResetPasswordRequest 重置密码请求
 type ResetPasswordRequest struct {
-	Token       string `json:"token" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required"` // SHA-256 哈希值
+	Token          string `json:"token" binding:"required"`
+	NewPassword    string `json:"new_password" binding:"required"`                        // 格式由 password_format 指定
+	PasswordFormat string `json:"password_format" binding:"omitempty,oneof=sha256 plain"` // 默认 sha256
 }
 
 // UpdateProfileRequest 更新用户资料请求
@@ -38,9 +66,10 @@ type UpdateProfileRequest struct {
 
 // ChangePasswordRequest 修改密码请求
 type ChangePasswordRequest struct {
-	OldPassword    string `json:"old_password" binding:"required"`     // SHA-256 哈希值
-	NewPassword    string `json:"new_password" binding:"required"`     // SHA-256 哈希值
-	TargetUsername string `json:"target_username" binding:"omitempty"` // 可选，仅管理员
+	OldPassword    string `json:"old_password" binding:"required"`                        // 格式由 password_format 指定
+	NewPassword    string `json:"new_password" binding:"required"`                        // 格式由 password_format 指定
+	PasswordFormat string `json:"password_format" binding:"omitempty,oneof=sha256 plain"` // 默认 sha256，同时适用于新旧密码
+	TargetUsername string `json:"target_username" binding:"omitempty"`                    // 可选，仅管理员
 }
 
 // CheckUsernameRequest 检查用户名可用性请求
@@ -52,3 +81,122 @@ type CheckUsernameRequest struct {
 type CheckEmailRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
+
+// UserListQuery 用户列表查询参数（管理员）
+type UserListQuery struct {
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	UserType    string `form:"user_type" binding:"omitempty,oneof=system app"`
+	Status      string `form:"status" binding:"omitempty,oneof=active inactive"`
+	CreatedFrom string `form:"created_from" binding:"omitempty,datetime=2006-01-02"` // 创建日期起（含）
+	CreatedTo   string `form:"created_to" binding:"omitempty,datetime=2006-01-02"`   // 创建日期止（含）
+	Search      string `form:"q" binding:"omitempty,max=100"`                         // 用户名/邮箱模糊搜索
+	Sort        string `form:"sort"`                                                  // 排序，格式为 字段:asc|desc
+	Mode        string `form:"mode" binding:"omitempty,oneof=offset cursor"`          // 分页方式，默认 offset
+	Cursor      string `form:"cursor"`                                                // 游标分页时上一页返回的 next_cursor
+}
+
+// AuditEventQuery 审计日志查询参数（管理员）
+type AuditEventQuery struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	ActorID    *uint  `form:"actor_id" binding:"omitempty,min=1"`
+	Actor      string `form:"actor" binding:"omitempty,max=100"` // 操作者用户名
+	Action     string `form:"action" binding:"omitempty,max=64"` // 以 . 或 * 结尾时按前缀匹配
+	TargetType string `form:"target_type" binding:"omitempty,max=32"`
+	TargetID   string `form:"target_id" binding:"omitempty,max=64"`
+	Result     string `form:"result" binding:"omitempty,oneof=success failure"`
+	IP         string `form:"ip" binding:"omitempty,ip"`
+	From       string `form:"from" binding:"omitempty,datetime=2006-01-02"` // 起始日期（含）
+	To         string `form:"to" binding:"omitempty,datetime=2006-01-02"`   // 截止日期（含）
+	Format     string `form:"format" binding:"omitempty,oneof=json csv"`    // 返回格式，默认 json
+}
+
+// AnalyticsQuery 分析数据通用查询参数
+type AnalyticsQuery struct {
+	Sort      string   `form:"sort"` // 排序，格式为 字段:asc|desc
+	Page      int      `form:"page" binding:"omitempty,min=1"`
+	PageSize  int      `form:"page_size" binding:"omitempty,min=1"`        // 每页数量，超过100时按100处理
+	Limit     int      `form:"limit" binding:"omitempty,min=1"`            // 兼容旧参数，等同于 page_size
+	Min       *float64 `form:"min"`                                        // 数值下限（含）
+	Max       *float64 `form:"max"`                                        // 数值上限（含）
+	Contains  string   `form:"contains" binding:"omitempty,max=100"`       // 名称包含
+	With      string   `form:"with"`                                       // 附加字段，逗号分隔：share,cumulative,rank,summary
+	Others    bool     `form:"others"`                                     // 追加“其他”行，合并未返回的数据项
+	Threshold float64  `form:"threshold" binding:"omitempty,gt=0,lte=100"` // 占比低于该百分比的数据项合并到“其他”
+}
+
+// TrendQuery 趋势查询参数
+type TrendQuery struct {
+	From     string `form:"from" binding:"omitempty,datetime=2006-01"` // 起始年月（含），默认截止年月前11个月
+	To       string `form:"to" binding:"omitempty,datetime=2006-01"`   // 截止年月（含），默认最新数据所在年月
+	Names    string `form:"names" binding:"omitempty,max=1000"`        // 指定名称，逗号分隔
+	Contains string `form:"contains" binding:"omitempty,max=100"`      // 名称包含
+	Top      int    `form:"top" binding:"omitempty,min=1,max=50"`      // 未指定名称时返回区间合计最高的前N个，默认10
+}
+
+// CompareQuery 同比/环比对比查询参数
+type CompareQuery struct {
+	Period        string `form:"period" binding:"omitempty,datetime=2006-01"`         // 本期年月，默认最新数据所在年月
+	Mode          string `form:"mode" binding:"omitempty,oneof=yoy mom custom"`       // 对比方式，默认 mom
+	ComparePeriod string `form:"compare_period" binding:"omitempty,datetime=2006-01"` // mode=custom 时的对比年月
+	Sort          string `form:"sort"`                                                // 排序，格式为 字段:asc|desc，默认 current:desc
+	Limit         int    `form:"limit" binding:"omitempty,min=1,max=500"`             // 返回的对比项数量，默认全部
+}
+
+// EnergyAnalysisQuery 能源结构分析查询参数
+type EnergyAnalysisQuery struct {
+	By   string `form:"by" binding:"omitempty,oneof=city car_level"` // 分维度统计渗透率：city 或 car_level，默认不分维度
+	Sort string `form:"sort"`                                        // 分维度结果排序，格式为 字段:asc|desc，默认 total:desc
+	Top  int    `form:"top" binding:"omitempty,min=1,max=500"`       // 分维度结果返回的数量，默认20
+}
+
+// BreakdownQuery 交叉分析查询参数
+type BreakdownQuery struct {
+	Rows    string `form:"rows" binding:"required"`                   // 行维度
+	Cols    string `form:"cols" binding:"required"`                   // 列维度
+	Measure string `form:"measure"`                                   // 度量，默认 sales
+	From    string `form:"from" binding:"omitempty,datetime=2006-01"` // 起始年月（含）
+	To      string `form:"to" binding:"omitempty,datetime=2006-01"`   // 截止年月（含）
+	RowTop  int    `form:"row_top" binding:"omitempty,min=1,max=200"` // 返回合计最高的前N行，默认20
+	ColTop  int    `form:"col_top" binding:"omitempty,min=1,max=200"` // 返回合计最高的前N列，默认10
+	Others  bool   `form:"others"`                                    // 未返回的行列合并为“其他”
+}
+
+// DrilldownQuery 下钻查询参数
+type DrilldownQuery struct {
+	Path   []string `form:"path" binding:"omitempty,max=5,dive,required,max=255"` // 从顶层开始的节点名称，可重复，如 path=华东&path=江苏省
+	From   string   `form:"from" binding:"omitempty,datetime=2006-01"`            // 起始年月（含）
+	To     string   `form:"to" binding:"omitempty,datetime=2006-01"`              // 截止年月（含）
+	Sort   string   `form:"sort"`                                                 // 子节点排序，格式为 字段:asc|desc，默认 value:desc
+	Top    int      `form:"top" binding:"omitempty,min=1,max=500"`                // 返回的子节点数量，默认全部
+	Others bool     `form:"others"`                                               // 未返回的子节点合并为“其他”
+}
+
+// UpdateUserStatusRequest 修改用户状态请求（管理员）
+type UpdateUserStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active inactive"`
+}
+
+// UpdateUserTypeRequest 修改用户类型请求（管理员）
+type UpdateUserTypeRequest struct {
+	UserType string `json:"user_type" binding:"required,oneof=system app"`
+}
+
+// MFAVerifyRequest 登录二次验证请求
+type MFAVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP 验证码或恢复码
+}
+
+// MFACodeRequest 确认绑定/关闭二次验证请求
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"` // TOTP 验证码或恢复码
+}
+
+// CreateAPIKeyRequest 创建个人 API 密钥请求
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,required"` // 权限范围，不能超出当前角色的权限
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1"`     // 有效天数，不填使用默认值
+}
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"time"
+
+	"go-web/pkg/useragent"
+)
+
 // APIResponse 统一的API响应基类
 type APIResponse struct {
 	Code    int         `json:"code"`
@@ -37,6 +43,17 @@ type LoginResponse struct {
 	} `json:"data"`
 }
 
+// MFAChallengeResponse 登录需要二次验证时的响应
+type MFAChallengeResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		MFARequired    bool   `json:"mfa_required"`
+		ChallengeToken string `json:"challenge_token"`
+		ExpiresIn      int64  `json:"expires_in"`
+	} `json:"data"`
+}
+
 // TokenResponse 令牌响应
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
@@ -81,3 +98,37 @@ type PaginationResponse struct {
 	Limit int `json:"limit"`
 	Pages int `json:"pages"`
 }
+
+// CursorPaginationResponse 游标分页响应
+type CursorPaginationResponse struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// PagedResponse 分页列表响应，Pagination 为 PaginationResponse 或 CursorPaginationResponse
+type PagedResponse struct {
+	Code       int         `json:"code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+	Pagination interface{} `json:"pagination"`
+}
+
+// UserListItem 管理员用户列表项，附带登录锁定状态
+type UserListItem struct {
+	User
+	Locked      bool       `json:"locked"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+// SessionInfo 登录会话（设备）信息，ID 为会话族ID
+type SessionInfo struct {
+	ID         string         `json:"id"`
+	Device     useragent.Info `json:"device"`
+	UserAgent  string         `json:"user_agent"`
+	IPAddress  string         `json:"ip_address"`
+	CreatedAt  time.Time      `json:"created_at"`   // 登录时间
+	LastSeenAt time.Time      `json:"last_seen_at"` // 最近活跃时间
+	ExpiresAt  time.Time      `json:"expires_at"`   // 不再刷新时的失效时间
+	Current    bool           `json:"current"`      // 是否为发起本次请求的会话
+}
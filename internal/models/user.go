@@ -2,6 +2,8 @@ package models
 
 import (
     "time"
+
+    "gorm.io/gorm"
 )
 
 // 用户类型
@@ -22,27 +24,52 @@ const (
 
 // User 用户模型
 type User struct {
-    ID           uint       `json:"id" gorm:"primaryKey;autoIncrement"`
-    Username     string     `json:"username" gorm:"size:50;uniqueIndex;not null"`
-    Email        string     `json:"email" gorm:"size:100;uniqueIndex;not null"`
-    PasswordHash string     `json:"-" gorm:"size:255;not null"`
-    UserType     UserType   `json:"user_type" gorm:"type:ENUM('system','app');not null;default:'app'"`
-    Status       UserStatus `json:"status" gorm:"type:ENUM('active','inactive');not null;default:'active'"`
-    CreatedAt    time.Time  `json:"created_at"`
-    UpdatedAt    time.Time  `json:"updated_at"`
+    ID                       uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+    Username                 string         `json:"username" gorm:"size:50;uniqueIndex;not null"`
+    Email                    string         `json:"email" gorm:"size:100;uniqueIndex;not null"`
+    PasswordHash             string         `json:"-" gorm:"size:255;not null"`
+    UserType                 UserType       `json:"user_type" gorm:"type:ENUM('system','app');not null;default:'app'"`
+    Status                   UserStatus     `json:"status" gorm:"type:ENUM('active','inactive');not null;default:'active'"`
+    PasswordResetRequired    bool           `json:"password_reset_required" gorm:"not null;default:false"`    // 管理员要求下次登录前重置密码
+    EmailVerificationPending bool           `json:"email_verification_pending" gorm:"not null;default:false"` // 注册后等待邮箱验证
+    EmailVerifiedAt          *time.Time     `json:"email_verified_at,omitempty"`                              // 邮箱验证时间
+    CreatedAt                time.Time      `json:"created_at"`
+    UpdatedAt                time.Time      `json:"updated_at"`
+    DeletedAt                gorm.DeletedAt `json:"-" gorm:"index"` // 软删除
 }
 
+// 会话吊销原因
+const (
+    SessionRevokeRotated         = "rotated"           // 刷新令牌已轮换
+    SessionRevokeReused          = "reuse_detected"    // 检测到旧刷新令牌被重复使用
+    SessionRevokeLogout          = "logout"            // 用户登出
+    SessionRevokeLogoutAll       = "logout_all"        // 用户在所有设备登出
+    SessionRevokePasswordReset   = "password_reset"    // 密码已重置
+    SessionRevokePasswordChange  = "password_change"   // 密码已修改
+    SessionRevokeAdminDeactivate = "admin_deactivate"  // 管理员停用账户
+    SessionRevokeAdminDelete     = "admin_delete"      // 管理员删除账户
+    SessionRevokeAdminForceReset = "admin_force_reset" // 管理员要求重置密码
+    SessionRevokeUser            = "user_revoke"       // 用户在设备管理中移除会话
+    SessionRevokeAdmin           = "admin_revoke"      // 管理员移除会话
+)
+
 // UserSession 用户会话模型
+// 每次刷新都会生成新的一行，同一次登录产生的所有行共享 FamilyID
 type UserSession struct {
-    ID                uint      `json:"id" gorm:"primaryKey;autoIncrement"`
-    UserID            uint      `json:"user_id" gorm:"not null"`
-    Token             string    `json:"token" gorm:"size:512;not null"`
-    RefreshToken      string    `json:"refresh_token" gorm:"size:255;not null"`
-    ExpiresAt         time.Time `json:"expires_at" gorm:"not null"`
-    RefreshExpiresAt  time.Time `json:"refresh_expires_at" gorm:"not null"`
-    UserAgent         string    `json:"user_agent,omitempty" gorm:"type:text"`
-    IPAddress         string    `json:"ip_address,omitempty" gorm:"size:45"`
-    CreatedAt         time.Time `json:"created_at"`
+    ID                uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+    UserID            uint       `json:"user_id" gorm:"not null"`
+    FamilyID          string     `json:"family_id" gorm:"size:64;not null;index"`
+    Token             string     `json:"-" gorm:"size:512;not null"`         // 访问令牌哈希
+    RefreshToken      string     `json:"-" gorm:"size:255;not null"`         // 刷新令牌哈希
+    ExpiresAt         time.Time  `json:"expires_at" gorm:"not null"`
+    RefreshExpiresAt  time.Time  `json:"refresh_expires_at" gorm:"not null"`
+    UserAgent         string     `json:"user_agent,omitempty" gorm:"type:text"`
+    IPAddress         string     `json:"ip_address,omitempty" gorm:"size:45"`
+    RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+    RevokeReason      string     `json:"revoke_reason,omitempty" gorm:"size:32"`
+    ReplacedByID      *uint      `json:"replaced_by_id,omitempty"`
+    LastSeenAt        *time.Time `json:"last_seen_at,omitempty"` // 最近一次使用访问令牌的时间
+    CreatedAt         time.Time  `json:"created_at"`
     
-    User              User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+    User              User       `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
\ No newline at end of file
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// UserIdentity 外部身份提供方（OIDC）账户与本地用户的绑定
+type UserIdentity struct {
+	ID          uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID      uint       `json:"user_id" gorm:"not null;index"`
+	Provider    string     `json:"provider" gorm:"size:50;not null;uniqueIndex:idx_provider_subject"` // 配置中的提供方名称
+	Subject     string     `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_provider_subject"` // ID Token 中的 sub
+	Email       string     `json:"email" gorm:"size:100"`                                             // 最近一次登录时 IdP 提供的邮箱
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,55 @@
+package routers
+
+import (
+	"go-web/internal/controller"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// publicAnalyticsEnabled 是否在公开路由组保留旧的分析接口（analytics.public_enabled），默认保留以兼容现有看板
+func publicAnalyticsEnabled() bool {
+	if !viper.IsSet("analytics.public_enabled") {
+		return true
+	}
+	return viper.GetBool("analytics.public_enabled")
+}
+
+// registerPublicAnalyticsRoutes 注册无需认证的旧分析接口
+// 只包含原有的公开接口，新增的分析接口只注册在需要认证的分析路由组中
+func registerPublicAnalyticsRoutes(group *gin.RouterGroup) {
+	group.GET("/energy/distribution", controller.GetEnergyDistribution)
+	group.GET("/city/sales", controller.GetCitySales)
+	group.GET("/city/top-sales", controller.GetTopCitySales)
+	group.GET("/brand/sales", controller.GetBrandSales)
+	group.GET("/brand/top-sales", controller.GetTopBrandSales)
+	group.GET("/brand/last-sales", controller.GetLastBrandSales)
+	// 汽车级别分布API
+	group.GET("/car-level/distribution", controller.GetCarLevelDistribution)
+	group.GET("/car-level/top", controller.GetTopCarLevels)
+	group.GET("/car-level/last", controller.GetLastCarLevels)
+}
+
+// registerAnalyticsRoutes 注册需要认证的分析数据路由
+func registerAnalyticsRoutes(group *gin.RouterGroup) {
+	SetupEnergyRoutes(group)
+	group.GET("/city/sales", controller.GetCitySales)
+	group.GET("/city/top-sales", controller.GetTopCitySales)
+	group.GET("/city/trend", controller.GetCityTrend)
+	group.GET("/city/compare", controller.GetCityComparison)
+	group.GET("/brand/sales", controller.GetBrandSales)
+	group.GET("/brand/top-sales", controller.GetTopBrandSales)
+	group.GET("/brand/last-sales", controller.GetLastBrandSales)
+	group.GET("/brand/trend", controller.GetBrandTrend)
+	group.GET("/brand/compare", controller.GetBrandComparison)
+	// 汽车级别分布API
+	group.GET("/car-level/distribution", controller.GetCarLevelDistribution)
+	group.GET("/car-level/top", controller.GetTopCarLevels)
+	group.GET("/car-level/last", controller.GetLastCarLevels)
+	group.GET("/car-level/trend", controller.GetCarLevelTrend)
+	group.GET("/car-level/compare", controller.GetCarLevelComparison)
+	// 交叉分析API
+	group.GET("/breakdown", controller.GetBreakdown)
+	// 下钻API：region（大区→省份→城市）、brand（品牌→车系→车型）
+	group.GET("/drilldown/:hierarchy", controller.GetDrilldown)
+}
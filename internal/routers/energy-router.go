@@ -11,8 +11,8 @@ func SetupEnergyRoutes(api *gin.RouterGroup) {
 	energy := api.Group("/energy")
 	{
 		energy.GET("/distribution", controller.GetEnergyDistribution)
-		// 未来可以添加更多能源相关路由
-		// energy.GET("/trend", controller.GetEnergyTrend)
-		// energy.GET("/analysis", controller.GetEnergyAnalysis)
+		energy.GET("/trend", controller.GetEnergyTrend)
+		energy.GET("/compare", controller.GetEnergyComparison)
+		energy.GET("/analysis", controller.GetEnergyAnalysis)
 	}
 }
@@ -2,6 +2,8 @@ package routers
 
 import (
 	"go-web/internal/controller"
+	"go-web/internal/middleware"
+	"go-web/internal/models"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -16,12 +18,15 @@ func SetupRouter() *gin.Engine {
 		// AllowAllOrigins: true,
 		AllowOrigins:     []string{"http://localhost:5173", "http://127.0.0.1:5173"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "X-API-Key"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * 60 * 60, // 12 hours
 	}))
 
+	// 令牌验证公钥
+	engine.GET("/.well-known/jwks.json", controller.JWKSHandler)
+
 	api := engine.Group("/api/v1")
 	{
 		// 认证路由
@@ -29,38 +34,68 @@ func SetupRouter() *gin.Engine {
 		{
 			auth.POST("/register", controller.RegisterHandler)
 			auth.POST("/login", controller.LoginHandler)
-			auth.POST("/logout", controller.LogoutHandler)
+			auth.POST("/logout", middleware.AuthRequired(), middleware.SessionRequired(), controller.LogoutHandler)
+			auth.POST("/logout-all", middleware.AuthRequired(), middleware.SessionRequired(), controller.LogoutAllHandler)
 			auth.POST("/refresh", controller.RefreshTokenHandler)
 			auth.POST("/forgot-password", controller.ForgotPasswordHandler)
 			auth.POST("/reset-password", controller.ResetPasswordHandler)
 			auth.POST("/check-username", controller.CheckUsernameHandler)
 			auth.POST("/check-email", controller.CheckEmailHandler)
+			auth.POST("/mfa/verify", controller.MFAVerifyHandler)
+			auth.POST("/verify-email", controller.VerifyEmailHandler)
+			auth.POST("/resend-verification", controller.ResendVerificationHandler)
+			auth.GET("/oauth/providers", controller.OAuthProvidersHandler)
+			auth.GET("/oauth/:provider/authorize", controller.OAuthAuthorizeHandler)
+			auth.POST("/oauth/:provider/callback", controller.OAuthCallbackHandler)
 		}
 
 		// 公开路由
 		public := api.Group("/public")
 		{
 			public.GET("/health", controller.HealthHandler)
-			public.GET("/energy/distribution", controller.GetEnergyDistribution)
-			public.GET("/city/sales", controller.GetCitySales)
-			public.GET("/city/top-sales", controller.GetTopCitySales)
-			public.GET("/brand/sales", controller.GetBrandSales)
-			public.GET("/brand/top-sales", controller.GetTopBrandSales)
-			public.GET("/brand/last-sales", controller.GetLastBrandSales)
-			// 汽车级别分布API
-			public.GET("/car-level/distribution", controller.GetCarLevelDistribution)
-			public.GET("/car-level/top", controller.GetTopCarLevels)
-			public.GET("/car-level/last", controller.GetLastCarLevels)
+			if publicAnalyticsEnabled() {
+				registerPublicAnalyticsRoutes(public)
+			}
+		}
+
+		// 分析数据（需要认证，支持访问令牌和 X-API-Key）
+		analytics := api.Group("/analytics")
+		analytics.Use(middleware.AuthRequired(), middleware.RequirePermission(models.PermAnalyticsRead))
+		{
+			registerAnalyticsRoutes(analytics)
 		}
 
 		// 受保护的路由（需要认证）
 		protected := api.Group("/protected")
+		protected.Use(middleware.AuthRequired())
 		{
-			users := protected.Group("/users")
+			// 账户管理只允许使用登录会话，不接受 API 密钥
+			users := protected.Group("/users", middleware.SessionRequired())
 			users.GET("/profile", controller.GetProfileHandler)
 			users.PUT("/profile", controller.UpdateProfileHandler)
 			users.PUT("/password", controller.ChangePasswordHandler)
-			users.GET("", controller.GetUsersListHandler) // 获取用户列表（仅管理员）
+			users.GET("/mfa", controller.GetMFAStatusHandler)
+			users.POST("/mfa/enroll", controller.EnrollMFAHandler)
+			users.POST("/mfa/confirm", controller.ConfirmMFAHandler)
+			users.POST("/mfa/disable", controller.DisableMFAHandler)
+			users.GET("/api-keys", controller.ListAPIKeysHandler)
+			users.POST("/api-keys", controller.CreateAPIKeyHandler)
+			users.DELETE("/api-keys/:keyId", controller.RevokeAPIKeyHandler)
+			users.GET("/sessions", controller.ListSessionsHandler)
+			users.DELETE("/sessions/:sessionId", controller.RevokeSessionHandler)
+			users.GET("", middleware.RequirePermission(models.PermUsersRead), controller.GetUsersListHandler) // 获取用户列表（仅管理员）
+			users.GET("/:id/sessions", middleware.RequirePermission(models.PermUsersRead), controller.AdminListSessionsHandler)
+
+			// 用户管理（仅管理员）
+			admin := users.Group("/:id", middleware.RequirePermission(models.PermUsersWrite))
+			admin.PUT("/status", controller.UpdateUserStatusHandler)
+			admin.PUT("/type", controller.UpdateUserTypeHandler)
+			admin.POST("/force-password-reset", controller.ForcePasswordResetHandler)
+			admin.DELETE("", controller.DeleteUserHandler)
+			admin.DELETE("/sessions/:sessionId", controller.AdminRevokeSessionHandler)
+
+			// 审计日志（仅管理员）
+			protected.GET("/audit-events", middleware.SessionRequired(), middleware.RequirePermission(models.PermAuditRead), controller.ListAuditEventsHandler)
 		}
 	}
 
@@ -0,0 +1,38 @@
+package routers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 新增的分析接口只能通过需要认证的 /analytics 访问
+func TestPublicRoutesOnlyExposeLegacyAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	legacy := map[string]bool{
+		"/api/v1/public/health":                 true,
+		"/api/v1/public/energy/distribution":    true,
+		"/api/v1/public/city/sales":             true,
+		"/api/v1/public/city/top-sales":         true,
+		"/api/v1/public/brand/sales":            true,
+		"/api/v1/public/brand/top-sales":        true,
+		"/api/v1/public/brand/last-sales":       true,
+		"/api/v1/public/car-level/distribution": true,
+		"/api/v1/public/car-level/top":          true,
+		"/api/v1/public/car-level/last":         true,
+	}
+
+	analytics := 0
+	for _, r := range SetupRouter().Routes() {
+		if strings.HasPrefix(r.Path, "/api/v1/public/") && !legacy[r.Path] {
+			t.Errorf("公开路由组不应注册 %s", r.Path)
+		}
+		if strings.HasPrefix(r.Path, "/api/v1/analytics/") {
+			analytics++
+		}
+	}
+	if analytics <= len(legacy)-1 {
+		t.Errorf("/analytics 下只有 %d 个接口", analytics)
+	}
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go-web/pkg/logger"
+)
+
+// FileMailer 将邮件追加写入本地文件，不真正发送，适用于开发和测试环境
+type FileMailer struct {
+	mu   sync.Mutex
+	path string
+	from string
+}
+
+// NewFileMailer 创建文件邮件发送器
+func NewFileMailer(path string, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建邮件目录失败: %v", err)
+	}
+	return &FileMailer{path: path, from: from}, nil
+}
+
+// Send 写入邮件内容
+func (m *FileMailer) Send(msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("打开邮件文件失败: %v", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "----- %s -----\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), m.from, strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+	if err != nil {
+		return fmt.Errorf("写入邮件文件失败: %v", err)
+	}
+
+	logger.Infow("邮件已写入本地文件", "to", msg.To, "subject", msg.Subject, "path", m.path)
+	return nil
+}
@@ -0,0 +1,84 @@
+package mailer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Message 邮件内容（纯文本）
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(msg Message) error
+}
+
+var (
+	mu            sync.RWMutex
+	defaultMailer Mailer
+)
+
+// InitMailer 根据配置初始化全局邮件发送器
+// mail.driver: smtp 通过 SMTP 服务器发送；file（默认）写入本地文件，便于开发调试
+func InitMailer() error {
+	from := viper.GetString("mail.from")
+	if from == "" {
+		from = "noreply@localhost"
+	}
+
+	var m Mailer
+	switch driver := viper.GetString("mail.driver"); driver {
+	case "smtp":
+		port := viper.GetInt("mail.smtp.port")
+		if port == 0 {
+			port = 25
+		}
+		m = NewSMTPMailer(SMTPConfig{
+			Host:     viper.GetString("mail.smtp.host"),
+			Port:     port,
+			Username: viper.GetString("mail.smtp.username"),
+			Password: viper.GetString("mail.smtp.password"),
+			From:     from,
+		})
+	case "", "file":
+		path := viper.GetString("mail.file_path")
+		if path == "" {
+			path = "logs/mail.log"
+		}
+		fileMailer, err := NewFileMailer(path, from)
+		if err != nil {
+			return err
+		}
+		m = fileMailer
+	default:
+		return fmt.Errorf("不支持的邮件发送方式: %s", driver)
+	}
+
+	SetMailer(m)
+	return nil
+}
+
+// SetMailer 替换全局邮件发送器
+func SetMailer(m Mailer) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultMailer = m
+}
+
+// Send 使用全局邮件发送器发送邮件
+func Send(msg Message) error {
+	mu.RLock()
+	m := defaultMailer
+	mu.RUnlock()
+
+	if m == nil {
+		return fmt.Errorf("邮件发送器未初始化")
+	}
+	return m.Send(msg)
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary 一组数值的描述统计
+type Summary struct {
+	Count  int     `json:"count"`
+	Total  float64 `json:"total"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stddev"` // 总体标准差
+	Gini   float64 `json:"gini"`   // 基尼系数，0 表示完全均匀，越接近 1 越集中
+}
+
+// Summarize 计算数值的描述统计，不修改传入的切片
+func Summarize(values []float64) Summary {
+	n := len(values)
+	if n == 0 {
+		return Summary{}
+	}
+
+	sorted := make([]float64, n)
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	var total float64
+	for _, v := range sorted {
+		total += v
+	}
+	mean := total / float64(n)
+
+	var variance float64
+	for _, v := range sorted {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(n)
+
+	return Summary{
+		Count:  n,
+		Total:  total,
+		Mean:   mean,
+		Median: median(sorted),
+		StdDev: math.Sqrt(variance),
+		Gini:   gini(sorted, total),
+	}
+}
+
+// median 已升序排列数值的中位数
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// gini 已升序排列的非负数值的基尼系数
+// G = 2·Σ(i·x_i) / (n·Σx) − (n+1)/n，i 从 1 开始
+func gini(sorted []float64, total float64) float64 {
+	n := float64(len(sorted))
+	if total <= 0 || n == 0 {
+		return 0
+	}
+	var weighted float64
+	for i, v := range sorted {
+		weighted += float64(i+1) * v
+	}
+	return 2*weighted/(n*total) - (n+1)/n
+}
+
+// Percent 计算 part 占 total 的百分比，total 为0时返回0
+func Percent(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return part / total * 100
+}
+
+// Round 四舍五入到指定小数位
+func Round(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
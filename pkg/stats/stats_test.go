@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   Summary
+	}{
+		{name: "空输入", values: nil, want: Summary{}},
+		{name: "全为0", values: []float64{0, 0, 0}, want: Summary{Count: 3}},
+		{name: "单个值", values: []float64{42}, want: Summary{Count: 1, Total: 42, Mean: 42, Median: 42}},
+		{name: "完全均匀", values: []float64{5, 5, 5, 5}, want: Summary{Count: 4, Total: 20, Mean: 5, Median: 5}},
+		{name: "完全集中", values: []float64{0, 0, 0, 10}, want: Summary{Count: 4, Total: 10, Mean: 2.5, Median: 0, StdDev: math.Sqrt(18.75), Gini: 0.75}},
+		{name: "偶数个取中间两数均值", values: []float64{4, 1, 3, 2}, want: Summary{Count: 4, Total: 10, Mean: 2.5, Median: 2.5, StdDev: math.Sqrt(1.25), Gini: 0.25}},
+		{name: "奇数个", values: []float64{9, 2, 4, 4, 5, 5, 7, 4, 5}, want: Summary{Count: 9, Total: 45, Mean: 5, Median: 5, StdDev: math.Sqrt(32.0 / 9), Gini: 16.0 / 81}},
+	}
+	for _, tt := range tests {
+		got := Summarize(tt.values)
+		if got.Count != tt.want.Count || !near(got.Total, tt.want.Total) || !near(got.Mean, tt.want.Mean) ||
+			!near(got.Median, tt.want.Median) || !near(got.StdDev, tt.want.StdDev) || !near(got.Gini, tt.want.Gini) {
+			t.Errorf("%s: Summarize(%v) = %+v, 期望 %+v", tt.name, tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeKeepsInput(t *testing.T) {
+	values := []float64{3, 1, 2}
+	Summarize(values)
+	if !reflect.DeepEqual(values, []float64{3, 1, 2}) {
+		t.Fatalf("传入的切片被修改: %v", values)
+	}
+}
+
+func TestPercentAndRound(t *testing.T) {
+	if got := Percent(1, 0); got != 0 {
+		t.Errorf("Percent(1, 0) = %v", got)
+	}
+	if got := Round(Percent(1, 3), 2); got != 33.33 {
+		t.Errorf("Round(Percent(1, 3), 2) = %v", got)
+	}
+	if got := Round(-2.345, 1); got != -2.3 {
+		t.Errorf("Round(-2.345, 1) = %v", got)
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,312 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+)
+
+// legacyKeyID 仅配置 jwt.secret 时使用的密钥ID，也用于验证不带 kid 的旧令牌
+const legacyKeyID = "default"
+
+// SigningKey 签名密钥
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	ActiveFrom time.Time // 开始用于签名的时间，零值表示立即生效
+	RetireAt   time.Time // 停止用于签名的时间，零值表示不退役；退役后在令牌最长有效期内仍可验证
+
+	signKey   interface{} // []byte / *rsa.PrivateKey / ed25519.PrivateKey
+	verifyKey interface{} // []byte / *rsa.PublicKey / ed25519.PublicKey
+}
+
+// keyConfig jwt.keys 中单个密钥的配置
+type keyConfig struct {
+	Kid            string `mapstructure:"kid"`
+	Alg            string `mapstructure:"alg"`              // HS256, RS256, EdDSA
+	Secret         string `mapstructure:"secret"`           // HS256 密钥
+	PrivateKey     string `mapstructure:"private_key"`      // RS256/EdDSA PEM 私钥
+	PrivateKeyFile string `mapstructure:"private_key_file"` // RS256/EdDSA PEM 私钥文件
+	ActiveFrom     string `mapstructure:"active_from"`      // RFC3339
+	RetireAt       string `mapstructure:"retire_at"`        // RFC3339
+}
+
+// Keyring 密钥环：按 kid 管理多把密钥，支持按时间计划轮换
+type Keyring struct {
+	mu   sync.RWMutex
+	keys map[string]*SigningKey
+	now  func() time.Time
+}
+
+var keyring = &Keyring{keys: make(map[string]*SigningKey), now: time.Now}
+
+// InitKeyring 从配置加载密钥环，未配置任何密钥时返回错误
+// 优先读取 jwt.keys；未配置时将 jwt.secret 作为 kid=default 的 HS256 密钥
+func InitKeyring() error {
+	var configs []keyConfig
+	if err := viper.UnmarshalKey("jwt.keys", &configs); err != nil {
+		return fmt.Errorf("jwt.keys 配置格式错误: %v", err)
+	}
+
+	if len(configs) == 0 {
+		secret := viper.GetString("jwt.secret")
+		if secret == "" {
+			return errors.New("未配置 JWT 签名密钥（jwt.keys 或 jwt.secret）")
+		}
+		configs = append(configs, keyConfig{Kid: legacyKeyID, Alg: "HS256", Secret: secret})
+	}
+
+	keys := make(map[string]*SigningKey, len(configs))
+	for _, cfg := range configs {
+		key, err := parseKeyConfig(cfg)
+		if err != nil {
+			return err
+		}
+		if _, exists := keys[key.ID]; exists {
+			return fmt.Errorf("JWT 密钥ID重复: %s", key.ID)
+		}
+		keys[key.ID] = key
+	}
+
+	keyring.mu.Lock()
+	keyring.keys = keys
+	keyring.mu.Unlock()
+
+	if _, err := keyring.signingKey(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseKeyConfig 解析单个密钥配置
+func parseKeyConfig(cfg keyConfig) (*SigningKey, error) {
+	if cfg.Kid == "" {
+		return nil, errors.New("JWT 密钥缺少 kid")
+	}
+
+	key := &SigningKey{ID: cfg.Kid}
+
+	var err error
+	if key.ActiveFrom, err = parseKeyTime(cfg.ActiveFrom); err != nil {
+		return nil, fmt.Errorf("JWT 密钥 %s 的 active_from 格式错误: %v", cfg.Kid, err)
+	}
+	if key.RetireAt, err = parseKeyTime(cfg.RetireAt); err != nil {
+		return nil, fmt.Errorf("JWT 密钥 %s 的 retire_at 格式错误: %v", cfg.Kid, err)
+	}
+
+	switch cfg.Alg {
+	case "", "HS256":
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("JWT 密钥 %s 缺少 secret", cfg.Kid)
+		}
+		key.Method = jwt.SigningMethodHS256
+		key.signKey = []byte(cfg.Secret)
+		key.verifyKey = []byte(cfg.Secret)
+
+	case "RS256":
+		pemBytes, err := readPrivateKey(cfg)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("JWT 密钥 %s 解析 RSA 私钥失败: %v", cfg.Kid, err)
+		}
+		key.Method = jwt.SigningMethodRS256
+		key.signKey = privateKey
+		key.verifyKey = &privateKey.PublicKey
+
+	case "EdDSA":
+		pemBytes, err := readPrivateKey(cfg)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("JWT 密钥 %s 解析 Ed25519 私钥失败: %v", cfg.Kid, err)
+		}
+		privateKey := parsed.(ed25519.PrivateKey)
+		key.Method = jwt.SigningMethodEdDSA
+		key.signKey = privateKey
+		key.verifyKey = privateKey.Public().(ed25519.PublicKey)
+
+	default:
+		return nil, fmt.Errorf("JWT 密钥 %s 使用了不支持的算法: %s", cfg.Kid, cfg.Alg)
+	}
+
+	return key, nil
+}
+
+// readPrivateKey 读取内联或文件形式的 PEM 私钥
+func readPrivateKey(cfg keyConfig) ([]byte, error) {
+	if cfg.PrivateKey != "" {
+		return []byte(cfg.PrivateKey), nil
+	}
+	if cfg.PrivateKeyFile == "" {
+		return nil, fmt.Errorf("JWT 密钥 %s 缺少 private_key 或 private_key_file", cfg.Kid)
+	}
+	pemBytes, err := os.ReadFile(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取 JWT 密钥 %s 的私钥文件失败: %v", cfg.Kid, err)
+	}
+	return pemBytes, nil
+}
+
+// parseKeyTime 解析 RFC3339 时间，空字符串返回零值
+func parseKeyTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// signingKey 返回当前用于签名的密钥：已生效且未退役的密钥中生效时间最晚的一把
+func (k *Keyring) signingKey() (*SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := k.now()
+	var current *SigningKey
+	for _, key := range k.keys {
+		if now.Before(key.ActiveFrom) {
+			continue
+		}
+		if !key.RetireAt.IsZero() && !now.Before(key.RetireAt) {
+			continue
+		}
+		if current == nil || key.ActiveFrom.After(current.ActiveFrom) ||
+			(key.ActiveFrom.Equal(current.ActiveFrom) && key.ID > current.ID) {
+			current = key
+		}
+	}
+
+	if current == nil {
+		return nil, errors.New("没有可用于签名的 JWT 密钥")
+	}
+	return current, nil
+}
+
+// verificationKey 根据 kid 返回验证密钥，退役超过令牌最长有效期的密钥不再接受
+func (k *Keyring) verificationKey(kid string) (*SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if kid == "" {
+		kid = legacyKeyID
+	}
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("未知的 JWT 密钥ID: %s", kid)
+	}
+	if !key.RetireAt.IsZero() && k.now().After(key.RetireAt.Add(maxTokenTTL())) {
+		return nil, fmt.Errorf("JWT 密钥已停用: %s", kid)
+	}
+	return key, nil
+}
+
+// algorithms 返回密钥环中使用的全部签名算法
+func (k *Keyring) algorithms() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	seen := make(map[string]struct{})
+	var algs []string
+	for _, key := range k.keys {
+		alg := key.Method.Alg()
+		if _, ok := seen[alg]; !ok {
+			seen[alg] = struct{}{}
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+// sign 使用当前签名密钥签发令牌，并在头部写入 kid
+func sign(claims jwt.Claims) (string, error) {
+	key, err := keyring.signingKey()
+	if err != nil {
+		return "", err
+	}
+
+	t := jwt.NewWithClaims(key.Method, claims)
+	t.Header["kid"] = key.ID
+	return t.SignedString(key.signKey)
+}
+
+// keyFunc 根据令牌头部的 kid 选择验证密钥，并要求算法与密钥匹配
+func keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	key, err := keyring.verificationKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("令牌算法 %s 与密钥 %s 不匹配", t.Method.Alg(), kid)
+	}
+	return key.verifyKey, nil
+}
+
+// JWK 单个 JSON Web Key（仅公钥）
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // OKP 曲线
+	X   string `json:"x,omitempty"`   // OKP 公钥
+}
+
+// JWKSet JSON Web Key Set
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicJWKS 返回仍可用于验证的非对称密钥的公钥集合（HS256 密钥不公开）
+func PublicJWKS() JWKSet {
+	keyring.mu.RLock()
+	defer keyring.mu.RUnlock()
+
+	now := keyring.now()
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range keyring.keys {
+		if !key.RetireAt.IsZero() && now.After(key.RetireAt.Add(maxTokenTTL())) {
+			continue
+		}
+
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
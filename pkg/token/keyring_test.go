@@ -0,0 +1,193 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+)
+
+var (
+	keyT0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	keyT1 = keyT0.Add(30 * 24 * time.Hour) // old 退役、rsa 生效
+	keyT2 = keyT1.Add(30 * 24 * time.Hour) // ed 生效
+)
+
+// setupRotation 加载 HS256 → RS256 → EdDSA 的轮换计划，返回设置密钥环当前时间的函数
+func setupRotation(t *testing.T) func(time.Time) {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	viper.Set("jwt.keys", []map[string]interface{}{
+		{"kid": "old", "alg": "HS256", "secret": "old-secret", "retire_at": keyT1.Format(time.RFC3339)},
+		{"kid": "rsa", "alg": "RS256", "private_key": string(rsaPEM), "active_from": keyT1.Format(time.RFC3339)},
+		{"kid": "ed", "alg": "EdDSA", "private_key": string(edPEM), "active_from": keyT2.Format(time.RFC3339)},
+	})
+	oldNow := keyring.now
+	t.Cleanup(func() {
+		viper.Set("jwt.keys", nil)
+		keyring.now = oldNow
+	})
+
+	setNow := func(now time.Time) { keyring.now = func() time.Time { return now } }
+	setNow(keyT0)
+	if err := InitKeyring(); err != nil {
+		t.Fatal(err)
+	}
+	return setNow
+}
+
+// tokenKid 返回令牌头部的 kid
+func tokenKid(t *testing.T, tokenString string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestKeyringSigningKey(t *testing.T) {
+	setNow := setupRotation(t)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		kid  string
+		alg  string
+	}{
+		{"轮换前", keyT0, "old", "HS256"},
+		{"old 退役前一刻", keyT1.Add(-time.Second), "old", "HS256"},
+		{"rsa 生效", keyT1, "rsa", "RS256"},
+		{"ed 生效", keyT2, "ed", "EdDSA"},
+	}
+	for _, tt := range tests {
+		setNow(tt.now)
+		signed, err := GenerateMFAChallengeTokenAt(1, tt.now)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if kid := tokenKid(t, signed); kid != tt.kid {
+			t.Errorf("%s: kid = %s, 期望 %s", tt.name, kid, tt.kid)
+		}
+		claims, err := ParseTokenOfTypeAt(signed, TypeMFAChallenge, tt.now)
+		if err != nil || claims.UserID != 1 {
+			t.Errorf("%s: 解析失败 claims = %+v, err = %v", tt.name, claims, err)
+		}
+		parsed, _, _ := jwt.NewParser().ParseUnverified(signed, &Claims{})
+		if alg := parsed.Method.Alg(); alg != tt.alg {
+			t.Errorf("%s: alg = %s, 期望 %s", tt.name, alg, tt.alg)
+		}
+	}
+}
+
+func TestKeyringVerification(t *testing.T) {
+	setNow := setupRotation(t)
+
+	// old 仍在签名期内时签发的令牌
+	issuedAt := keyT1.Add(-time.Minute)
+	setNow(issuedAt)
+	signed, err := GenerateMFAChallengeTokenAt(1, issuedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forged := func(kid string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+			UserID:           1,
+			TokenType:        TypeMFAChallenge,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(issuedAt.Add(time.Minute))},
+		})
+		if kid != "" {
+			tok.Header["kid"] = kid
+		}
+		s, err := tok.SignedString([]byte("old-secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		now   time.Time // 密钥环当前时间
+		ok    bool
+	}{
+		{"签名密钥仍在使用", signed, issuedAt, true},
+		{"退役但在令牌最长有效期内", signed, keyT1.Add(maxTokenTTL() - time.Second), true},
+		{"退役超过令牌最长有效期", signed, keyT1.Add(maxTokenTTL() + time.Second), false},
+		{"未知的 kid", forged("missing"), issuedAt, false},
+		{"缺少 kid 且没有 default 密钥", forged(""), issuedAt, false},
+		{"kid 与算法不匹配", forged("rsa"), issuedAt, false},
+	}
+	for _, tt := range tests {
+		setNow(tt.now)
+		// 令牌有效期按签发时间校验，只考察密钥是否仍被接受
+		_, err := ParseTokenOfTypeAt(tt.token, TypeMFAChallenge, issuedAt)
+		if tt.ok && err != nil {
+			t.Errorf("%s: 意外错误 %v", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: 应被拒绝", tt.name)
+		}
+	}
+}
+
+func TestPublicJWKS(t *testing.T) {
+	setupRotation(t)
+
+	set := PublicJWKS()
+	var kids []string
+	for _, key := range set.Keys {
+		kids = append(kids, key.Kid+":"+key.Kty+":"+key.Alg)
+	}
+	if got, want := strings.Join(kids, ","), "ed:OKP:EdDSA,rsa:RSA:RS256"; got != want {
+		t.Fatalf("JWKS = %s, 期望 %s（HS256 密钥不能公开）", got, want)
+	}
+
+	raw, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range decoded.Keys {
+		for _, private := range []string{"d", "p", "q", "dp", "dq", "qi", "k"} {
+			if _, ok := key[private]; ok {
+				t.Errorf("密钥 %v 包含私有字段 %s", key["kid"], private)
+			}
+		}
+	}
+	if strings.Contains(string(raw), "old-secret") {
+		t.Error("JWKS 泄露了 HS256 密钥")
+	}
+}
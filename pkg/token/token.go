@@ -1,60 +1,197 @@
 package token
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/viper"
 )
 
+// 令牌类型
+const (
+	TypeAccess       = "access"        // 访问令牌
+	TypeMFAChallenge = "mfa_challenge" // 登录二次验证挑战令牌
+	TypeEmailVerify  = "email_verify"  // 注册邮箱验证令牌
+)
+
+// MFAChallengeTTL 二次验证挑战令牌有效期
+const MFAChallengeTTL = 5 * time.Minute
+
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID    uint   `json:"user_id"`
+	SessionID string `json:"sid,omitempty"` // 会话族ID，用于吊销检查
+	TokenType string `json:"typ"`
+	Email     string `json:"email,omitempty"` // 待验证的邮箱，仅邮箱验证令牌使用
 	jwt.RegisteredClaims
 }
 
-// getSecret 动态获取 JWT 密钥
-func getSecret() []byte {
-	secret := viper.GetString("jwt.secret")
-	if secret == "" {
-		// 如果未配置，使用默认值（不推荐用于生产环境）
-		secret = "your-super-secret-jwt-key-change-in-production"
-	}
-	return []byte(secret)
-}
-
-// 生成访问令牌（JWT）
-func GenerateAccessToken(userID uint) (string, error) {
-	// 读取过期时间配置，默认15分钟
-	expire := viper.GetString("jwt.access_token_expire")
+// parseExpire 解析过期时间配置，在 time.ParseDuration 基础上支持 "7d" 这样的天数写法
+func parseExpire(key string, fallback time.Duration) time.Duration {
+	expire := strings.TrimSpace(viper.GetString(key))
 	if expire == "" {
-		expire = "15m"
+		return fallback
+	}
+	if days, ok := strings.CutSuffix(expire, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return fallback
+		}
+		return time.Duration(n) * 24 * time.Hour
 	}
 	dur, err := time.ParseDuration(expire)
+	if err != nil || dur <= 0 {
+		return fallback
+	}
+	return dur
+}
+
+// AccessTokenTTL 访问令牌有效期，默认15分钟
+func AccessTokenTTL() time.Duration {
+	return parseExpire("jwt.access_token_expire", 15*time.Minute)
+}
+
+// RefreshTokenTTL 刷新令牌有效期，默认7天
+func RefreshTokenTTL() time.Duration {
+	return parseExpire("jwt.refresh_token_expire", 7*24*time.Hour)
+}
+
+// EmailVerificationTTL 邮箱验证令牌有效期，默认24小时
+func EmailVerificationTTL() time.Duration {
+	return parseExpire("email_verification.token_expire", 24*time.Hour)
+}
+
+// 生成访问令牌（JWT），sessionID 为令牌所属的会话族ID
+func GenerateAccessToken(userID uint, sessionID string) (string, error) {
+	expirationTime := time.Now().Add(AccessTokenTTL())
+
+	jti, err := RandomString(16)
 	if err != nil {
-		dur = 15 * time.Minute
+		return "", err
 	}
-	expirationTime := time.Now().Add(dur)
 
 	claims := &Claims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
+		TokenType: TypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(getSecret())
-	return tokenString, err
+	return sign(claims)
+}
+
+// maxTokenTTL 本服务签发的令牌的最长有效期，退役密钥在此期间内仍可验证
+func maxTokenTTL() time.Duration {
+	ttl := AccessTokenTTL()
+	if MFAChallengeTTL > ttl {
+		ttl = MFAChallengeTTL
+	}
+	if verify := EmailVerificationTTL(); verify > ttl {
+		ttl = verify
+	}
+	return ttl
+}
+
+// GenerateMFAChallengeToken 生成二次验证挑战令牌，只能用于兑换访问令牌，不能访问受保护接口
+func GenerateMFAChallengeToken(userID uint) (string, error) {
+	return GenerateMFAChallengeTokenAt(userID, time.Now())
+}
+
+// GenerateMFAChallengeTokenAt 以 now 作为签发时间生成二次验证挑战令牌，供注入时钟的调用方使用
+// 令牌带有随机 jti，兑换时据此保证只能使用一次
+func GenerateMFAChallengeTokenAt(userID uint, now time.Time) (string, error) {
+	jti, err := RandomString(16)
+	if err != nil {
+		return "", err
+	}
+
+	claims := &Claims{
+		UserID:    userID,
+		TokenType: TypeMFAChallenge,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(MFAChallengeTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return sign(claims)
+}
+
+// GenerateEmailVerificationToken 生成注册邮箱验证令牌，令牌绑定用户ID和邮箱，邮箱变更后自动失效
+func GenerateEmailVerificationToken(userID uint, email string) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:    userID,
+		TokenType: TypeEmailVerify,
+		Email:     email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(EmailVerificationTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return sign(claims)
+}
+
+// GenerateRefreshToken 生成不透明的随机刷新令牌（仅返回给客户端，数据库中只保存其哈希）
+func GenerateRefreshToken() (string, error) {
+	return RandomString(32)
+}
+
+// RandomString 生成 n 字节随机数据的 URL 安全 Base64 字符串
+func RandomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken 计算令牌的 SHA-256 哈希，用于落库和查询
+func HashToken(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(sum[:])
 }
 
 // 解析并验证 token
 func ParseToken(tokenString string) (*Claims, error) {
+	return parseToken(tokenString, time.Now)
+}
+
+// parseToken 解析令牌，now 用于校验有效期
+func parseToken(tokenString string, now func() time.Time) (*Claims, error) {
 	claims := &Claims{}
-	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return getSecret(), nil
-	})
+	_, err := jwt.ParseWithClaims(tokenString, claims, keyFunc,
+		jwt.WithValidMethods(keyring.algorithms()), jwt.WithTimeFunc(now))
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// ParseTokenOfType 解析令牌并要求令牌类型匹配
+func ParseTokenOfType(tokenString string, tokenType string) (*Claims, error) {
+	return ParseTokenOfTypeAt(tokenString, tokenType, time.Now())
+}
+
+// ParseTokenOfTypeAt 以 now 作为当前时间解析令牌并要求令牌类型匹配，供注入时钟的调用方使用
+func ParseTokenOfTypeAt(tokenString string, tokenType string, now time.Time) (*Claims, error) {
+	claims, err := parseToken(tokenString, func() time.Time { return now })
 	if err != nil {
 		return nil, err
 	}
+	if claims.TokenType != tokenType {
+		return nil, fmt.Errorf("令牌类型错误: %s", claims.TokenType)
+	}
 	return claims, nil
 }
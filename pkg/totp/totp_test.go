@@ -0,0 +1,81 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret RFC 6238 附录 B 中 SHA1 使用的密钥 "12345678901234567890"
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// RFC 6238 附录 B 的 SHA1 测试向量，8 位验证码取后 6 位
+func TestGenerateCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		got, err := GenerateCode(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("T=%d: %v", tt.unix, err)
+		}
+		if want := tt.want[len(tt.want)-Digits:]; got != want {
+			t.Errorf("T=%d: 验证码 = %s, 期望 %s", tt.unix, got, want)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	counter := Counter(now)
+
+	tests := []struct {
+		name   string
+		offset int64 // 验证码所在时间步与当前时间步的差
+		skew   int
+		ok     bool
+	}{
+		{"当前时间步", 0, 1, true},
+		{"上一个时间步", -1, 1, true},
+		{"下一个时间步", 1, 1, true},
+		{"超出偏差（过去）", -2, 1, false},
+		{"超出偏差（未来）", 2, 1, false},
+		{"不允许偏差", 1, 0, false},
+	}
+	for _, tt := range tests {
+		code, err := GenerateCode(rfcSecret, time.Unix((counter+tt.offset)*Period, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		step, ok := Validate(rfcSecret, code, now, tt.skew)
+		if ok != tt.ok {
+			t.Errorf("%s: ok = %v, 期望 %v", tt.name, ok, tt.ok)
+		}
+		if ok && step != counter+tt.offset {
+			t.Errorf("%s: 时间步 = %d, 期望 %d", tt.name, step, counter+tt.offset)
+		}
+	}
+}
+
+func TestValidateRejectsMalformed(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870821", "abcdef"} {
+		if _, ok := Validate(rfcSecret, code, now, 1); ok {
+			t.Errorf("验证码 %q 不应通过", code)
+		}
+	}
+	// 密钥兼容小写和空格
+	secret := strings.ToLower(rfcSecret[:8]) + " " + rfcSecret[8:]
+	if _, ok := Validate(secret, "287082", now, 0); !ok {
+		t.Error("小写带空格的密钥应能解码")
+	}
+}
@@ -0,0 +1,130 @@
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 设备类型
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// Info 从 User-Agent 中解析出的客户端信息
+type Info struct {
+	Browser        string `json:"browser"`
+	BrowserVersion string `json:"browser_version,omitempty"`
+	OS             string `json:"os"`
+	OSVersion      string `json:"os_version,omitempty"`
+	DeviceType     string `json:"device_type"`
+}
+
+// browserRule 浏览器识别规则，按顺序匹配，基于 Chromium 的浏览器必须排在 Chrome 之前
+type browserRule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+var browserRules = []browserRule{
+	{"Edge", regexp.MustCompile(`(?:Edg|EdgA|EdgiOS|Edge)/([\d.]+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|Opera)/([\d.]+)`)},
+	{"WeChat", regexp.MustCompile(`MicroMessenger/([\d.]+)`)},
+	{"QQ Browser", regexp.MustCompile(`QQBrowser/([\d.]+)`)},
+	{"UC Browser", regexp.MustCompile(`UCBrowser/([\d.]+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/([\d.]+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/([\d.]+)`)},
+	{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/([\d.]+)`)},
+	{"Safari", regexp.MustCompile(`Version/([\d.]+).*Safari/`)},
+	{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([\d.]+)`)},
+	{"curl", regexp.MustCompile(`^curl/([\d.]+)`)},
+	{"Postman", regexp.MustCompile(`PostmanRuntime/([\d.]+)`)},
+	{"Go HTTP Client", regexp.MustCompile(`^Go-http-client/([\d.]+)`)},
+	{"Python Requests", regexp.MustCompile(`python-requests/([\d.]+)`)},
+}
+
+var (
+	windowsPattern = regexp.MustCompile(`Windows NT ([\d.]+)`)
+	iosPattern     = regexp.MustCompile(`(?:iPhone|CPU) OS ([\d_]+)`)
+	macPattern     = regexp.MustCompile(`Mac OS X ([\d_.]+)`)
+	androidPattern = regexp.MustCompile(`Android ([\d.]+)`)
+	botPattern     = regexp.MustCompile(`(?i)bot|crawler|spider|slurp`)
+)
+
+// windowsVersions Windows NT 内核版本与产品名称的对应关系
+var windowsVersions = map[string]string{
+	"10.0": "10/11",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// Parse 解析 User-Agent，无法识别的字段返回 Unknown
+func Parse(ua string) Info {
+	info := Info{Browser: "Unknown", OS: "Unknown", DeviceType: DeviceUnknown}
+	if strings.TrimSpace(ua) == "" {
+		return info
+	}
+
+	for _, rule := range browserRules {
+		if m := rule.pattern.FindStringSubmatch(ua); m != nil {
+			info.Browser = rule.name
+			info.BrowserVersion = majorMinor(m[1])
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "iPad"):
+		info.OS, info.DeviceType = "iPadOS", DeviceTablet
+		if m := iosPattern.FindStringSubmatch(ua); m != nil {
+			info.OSVersion = majorMinor(strings.ReplaceAll(m[1], "_", "."))
+		}
+	case strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPod"):
+		info.OS, info.DeviceType = "iOS", DeviceMobile
+		if m := iosPattern.FindStringSubmatch(ua); m != nil {
+			info.OSVersion = majorMinor(strings.ReplaceAll(m[1], "_", "."))
+		}
+	case strings.Contains(ua, "Android"):
+		info.OS, info.DeviceType = "Android", DeviceTablet
+		if strings.Contains(ua, "Mobile") {
+			info.DeviceType = DeviceMobile
+		}
+		if m := androidPattern.FindStringSubmatch(ua); m != nil {
+			info.OSVersion = majorMinor(m[1])
+		}
+	case strings.Contains(ua, "Windows"):
+		info.OS, info.DeviceType = "Windows", DeviceDesktop
+		if m := windowsPattern.FindStringSubmatch(ua); m != nil {
+			info.OSVersion = windowsVersions[m[1]]
+		}
+	case strings.Contains(ua, "Macintosh"):
+		info.OS, info.DeviceType = "macOS", DeviceDesktop
+		if m := macPattern.FindStringSubmatch(ua); m != nil {
+			info.OSVersion = majorMinor(strings.ReplaceAll(m[1], "_", "."))
+		}
+	case strings.Contains(ua, "CrOS"):
+		info.OS, info.DeviceType = "Chrome OS", DeviceDesktop
+	case strings.Contains(ua, "Linux"):
+		info.OS, info.DeviceType = "Linux", DeviceDesktop
+	}
+
+	if botPattern.MatchString(ua) {
+		info.DeviceType = DeviceBot
+	}
+	return info
+}
+
+// majorMinor 只保留主版本号和次版本号
+func majorMinor(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ".")
+}